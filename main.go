@@ -1,13 +1,14 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"runtime/debug"
 
 	"meowyplayer.com/source/client"
 	"meowyplayer.com/source/resource"
 	"meowyplayer.com/source/ui"
-	"meowyplayer.com/utility/assert"
 	"meowyplayer.com/utility/logger"
 )
 
@@ -20,11 +21,52 @@ func main() {
 	}()
 
 	logger.Initiate()
-	resource.MakeNecessaryPath()
 
-	window := ui.NewMainWindow()
+	//the warnings only cost a feature, they are told once the window is up
+	warnings := []error{}
+	warn := func(err error, message string) {
+		if err != nil {
+			logger.Error(err, message, 2)
+			warnings = append(warnings, fmt.Errorf("%v: %w", message, err))
+		}
+	}
+
+	//the player can't go on without these, and a library that failed to load must not be overwritten by an empty one
+	if err := resource.MakeNecessaryPath(); err != nil {
+		exit(fmt.Errorf("failed to create the library directories: %w", err))
+	}
+	if err := client.LoadConfig(); errors.Is(err, resource.ErrInvalidConfig) {
+		warn(err, "failed to apply the config")
+	} else if err != nil {
+		exit(fmt.Errorf("failed to load the config: %w", err))
+	}
 	inUse, err := client.LoadFromLocalCollection()
-	assert.NoErr(err, "failed to load from local collection")
+	if err != nil {
+		exit(fmt.Errorf("failed to load from local collection: %w", err))
+	}
+
+	warn(client.LoadHistory(), "failed to load the history")
+	warn(client.RegisterProviders(), "failed to register the providers")
+
+	window, err := ui.NewMainWindow()
+	if err != nil {
+		exit(err)
+	}
 	client.GetCollectionData().Set(&inUse)
+	warn(client.PurgeExpiredTrash(), "failed to purge the expired trash")
+	warn(client.LoadDownloads(), "failed to load the download queue")
+	warn(client.LoadSearchHistory(), "failed to load the search history")
+	client.StartPodcastRefresh()
+
+	if err := errors.Join(warnings...); err != nil {
+		ui.ShowStartupError(err)
+	}
 	window.ShowAndRun()
 }
+
+// log the fatal error and tell it in a dialog before quitting
+func exit(err error) {
+	logger.Error(err, "failed to start", 2)
+	ui.ShowFatalError(err)
+	os.Exit(1)
+}
@@ -2,6 +2,8 @@ package client
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"image"
 	"image/color"
@@ -9,24 +11,122 @@ import (
 	"math"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"slices"
 	"time"
 
 	"meowyplayer.com/source/resource"
-	"meowyplayer.com/utility/assert"
+	"meowyplayer.com/utility/network/fileformat"
 )
 
 func AddAlbum() error {
-	inUse := collectionData.Get()
+	musicLock.Lock()
+	defer musicLock.Unlock()
+
+	title := generateAlbumTitle("Album")
+	command := newCommand(fmt.Sprintf("add %v", title))
+	if _, err := addAlbum(command, title); err != nil {
+		return errors.Join(err, command.rollback())
+	}
+	return commit(command)
+}
+
+// create an album for each folder, filled with the music files inside
+// the folders are added all or none, the ones added before a failure are rolled back
+func AddAlbumsFromFolders(paths []string) error {
+	musicLock.Lock()
+	defer musicLock.Unlock()
+
+	command := newCommand(fmt.Sprintf("add %v folders", len(paths)))
+	if err := addAlbumsFromFolders(command, paths); err != nil {
+		return errors.Join(err, command.rollback())
+	}
+	return commit(command)
+}
+
+func addAlbumsFromFolders(command *command, paths []string) error {
+	for _, path := range paths {
+		if info, err := os.Stat(path); err != nil {
+			return err
+		} else if !info.IsDir() {
+			continue
+		}
+
+		title := filepath.Base(path)
+		if albumExists(title) {
+			title = generateAlbumTitle(title)
+		}
+		album, err := addAlbum(command, title)
+		if err != nil {
+			return err
+		}
+		if err := addMusicFromPaths(command, album, []string{path}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// create an album titled after the play list, with the play list thumbnail as the cover
+func AddAlbumFromPlayList(playList *fileformat.PlayListResult) (resource.Album, error) {
+	musicLock.Lock()
+	defer musicLock.Unlock()
+
+	title := playListAlbumTitle(playList)
+	command := newCommand(fmt.Sprintf("add %v", title))
+	album, err := addAlbumFromPlayList(command, title, playList)
+	if err != nil {
+		return resource.Album{}, errors.Join(err, command.rollback())
+	}
+	return *album, commit(command)
+}
+
+func playListAlbumTitle(playList *fileformat.PlayListResult) string {
+	title := playList.Title
+	if title == "" {
+		title = generateAlbumTitle("Album")
+	} else if albumExists(title) {
+		title = generateAlbumTitle(title)
+	}
+	return title
+}
+
+func addAlbumFromPlayList(command *command, title string, playList *fileformat.PlayListResult) (*resource.Album, error) {
+	album, err := addAlbum(command, title)
+	if err != nil {
+		return nil, err
+	}
+
+	//keep the random cover if the thumbnail can't be fetched
+	if playList.ThumbnailURL != "" {
+		thumbnail, err := thumbnailLoader.Fetch(context.Background(), playList.ThumbnailURL)
+		if err == nil {
+			err = command.writeFile(resource.CoverPath(album), thumbnail.Content())
+		}
+		if err != nil {
+			libraryLog.Warn("failed to set the cover", "album", title, "err", err)
+		}
+	}
+	return album, nil
+}
+
+func albumExists(title string) bool {
+	return slices.ContainsFunc(collectionData.Get().Albums, func(a resource.Album) bool { return a.Title == title })
+}
 
-	//generate title
+func generateAlbumTitle(name string) string {
 	title := ""
 	for i := 0; i < math.MaxInt; i++ {
-		title = fmt.Sprintf("Album (%v)", i)
-		if !slices.ContainsFunc(inUse.Albums, func(a resource.Album) bool { return a.Title == title }) {
+		title = fmt.Sprintf("%v (%v)", name, i)
+		if !albumExists(title) {
 			break
 		}
 	}
+	return title
+}
+
+func addAlbum(command *command, title string) (*resource.Album, error) {
+	inUse := collectionData.Get()
 
 	//generate album
 	album := resource.Album{Date: time.Now(), Title: title}
@@ -38,63 +138,87 @@ func AddAlbum() error {
 	iconImage.SetNRGBA(0, 0, iconColor)
 	imageData := bytes.Buffer{}
 	if err := png.Encode(&imageData, iconImage); err != nil {
-		return err
+		return nil, err
 	}
-	if err := os.WriteFile(resource.CoverPath(&album), imageData.Bytes(), os.ModePerm); err != nil {
-		return err
+	if err := command.writeFile(resource.CoverPath(&album), imageData.Bytes()); err != nil {
+		return nil, err
 	}
 
-	return reloadCollectionData()
+	return inUse.Albums.Back(), nil
 }
 
 func DeleteAlbum(album *resource.Album) error {
+	musicLock.Lock()
+	defer musicLock.Unlock()
+
 	collection := collectionData.Get()
 	index := slices.IndexFunc(collection.Albums, func(a resource.Album) bool { return a.Title == album.Title })
-	assert.Ensure(func() bool { return index != -1 })
+	if index == -1 {
+		return fmt.Errorf("%w: %q", resource.ErrAlbumNotFound, album.Title)
+	}
+	command := newCommand(fmt.Sprintf("delete %v", album.Title))
 
-	//remove album icon
-	if err := os.Remove(resource.CoverPath(album)); err != nil && !os.IsNotExist(err) {
+	//move album icon to the trash
+	cover, err := command.trashFile(resource.CoverPath(album))
+	if err != nil {
 		return err
 	}
 
-	//pop from the collection
+	//pop from the collection into the trash bin
+	trash := resource.Trash{Date: time.Now(), IsAlbum: true, Album: collection.Albums[index], Cover: cover}
+	trash.Album.Cover = nil
+	collection.Trash.PushBack(trash)
 	collection.Albums.Remove(index)
-	return reloadCollectionData()
+	return commit(command)
 }
 
 func UpdateAlbumTitle(album *resource.Album, title string) error {
-	if slices.ContainsFunc(collectionData.Get().Albums, func(a resource.Album) bool { return a.Title == title }) {
-		return fmt.Errorf("album \"%v\" already exists", title)
+	musicLock.Lock()
+	defer musicLock.Unlock()
+
+	if albumExists(title) {
+		return fmt.Errorf("%w: album %q", resource.ErrDuplicateTitle, title)
+	}
+	source, err := getSourceAlbum(album)
+	if err != nil {
+		return err
 	}
+	command := newCommand(fmt.Sprintf("rename %v to %v", album.Title, title))
 
 	//update timestamp
 	collectionData.Get().Date = time.Now()
-	source := getSourceAlbum(album)
 	source.Date = time.Now()
 
 	//rename the album cover
 	oldPath := resource.CoverPath(source)
 	source.Title = title
-	if err := os.Rename(oldPath, resource.CoverPath(source)); err != nil && !os.IsNotExist(err) {
-		return err
+	if err := command.moveFile(oldPath, resource.CoverPath(source)); err != nil {
+		return errors.Join(err, command.rollback())
 	}
-	return reloadCollectionData()
+	return commit(command)
 }
 
 func UpdateAlbumCover(album *resource.Album, iconPath string) error {
-	album = getSourceAlbum(album)
+	musicLock.Lock()
+	defer musicLock.Unlock()
+
+	album, err := getSourceAlbum(album)
+	if err != nil {
+		return err
+	}
+	icon, err := os.ReadFile(iconPath)
+	if err != nil {
+		return err
+	}
+	command := newCommand(fmt.Sprintf("update %v's cover", album.Title))
 
 	//update timestamp
 	album.Date = time.Now()
 	collectionData.Get().Date = time.Now()
 
 	//update cover image
-	icon, err := os.ReadFile(iconPath)
-	if err != nil {
-		return err
-	}
-	if err = os.WriteFile(resource.CoverPath(album), icon, os.ModePerm); err != nil {
-		return err
+	if err = command.writeFile(resource.CoverPath(album), icon); err != nil {
+		return errors.Join(err, command.rollback())
 	}
-	return reloadCollectionData()
+	return commit(command)
 }
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"meowyplayer.com/source/resource"
+	"meowyplayer.com/utility/cleaner"
+	"meowyplayer.com/utility/json"
+	"meowyplayer.com/utility/network/downloader"
+	"meowyplayer.com/utility/network/httpclient"
+	"meowyplayer.com/utility/pattern"
+)
+
+var configData pattern.Data[*resource.Config]
+
+func GetConfigData() *pattern.Data[*resource.Config] {
+	return &configData
+}
+
+// load the config on top of the default one, so that the missing fields keep their defaults
+// the invalid settings are replaced with the defaults and reported as ErrInvalidConfig, so that they can be fixed in the settings
+func LoadConfig() error {
+	config := resource.DefaultConfig()
+	if err := json.ReadFile(resource.ConfigPath(), &config); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	invalid := []error{}
+	if err := httpclient.Configure(config.Network); err != nil {
+		invalid = append(invalid, err)
+		config.Network = httpclient.DefaultConfig()
+		httpclient.Configure(config.Network)
+	}
+	if err := setTitleCleaner(&config.TitleRules); err != nil {
+		invalid = append(invalid, err)
+		config.TitleRules = cleaner.DefaultRules()
+		setTitleCleaner(&config.TitleRules)
+	}
+
+	//m4a used to be offered, but the player can't play it
+	if !slices.Contains(downloader.YtDlpFormats, config.YtDlp.Format) {
+		config.YtDlp.Format = downloader.DefaultYtDlpOptions().Format
+	}
+	configData.Set(&config)
+	if len(invalid) > 0 {
+		return fmt.Errorf("%w: %w", resource.ErrInvalidConfig, errors.Join(invalid...))
+	}
+	return nil
+}
+
+func updateConfig(update func(*resource.Config)) error {
+	config := *configData.Get()
+	update(&config)
+	if err := json.WriteFile(resource.ConfigPath(), &config); err != nil {
+		return err
+	}
+	configData.Set(&config)
+	return nil
+}
+
+// the invalid settings are rejected before they are saved
+func SetNetworkConfig(network httpclient.Config) error {
+	if err := httpclient.Configure(network); err != nil {
+		return err
+	}
+	return updateConfig(func(c *resource.Config) { c.Network = network })
+}
+
+func SetDownloadConcurrency(downloads int) error {
+	if err := updateConfig(func(c *resource.Config) { c.Downloads = max(downloads, 1) }); err != nil {
+		return err
+	}
+	return scheduleDownloads()
+}
+
+// the invalid rules are rejected before they are saved
+func SetTitleRules(rules cleaner.Rules) error {
+	if err := setTitleCleaner(&rules); err != nil {
+		return err
+	}
+	return updateConfig(func(c *resource.Config) { c.TitleRules = rules })
+}
+
+func SetYtDlpOptions(options downloader.YtDlpOptions) error {
+	if err := ytDlpDownloader.Configure(options); err != nil {
+		return err
+	}
+	return updateConfig(func(c *resource.Config) { c.YtDlp = options })
+}
+
+func SetTrashRetention(retention time.Duration) error {
+	return updateConfig(func(c *resource.Config) { c.TrashRetention = retention })
+}
@@ -0,0 +1,318 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"meowyplayer.com/source/resource"
+	"meowyplayer.com/utility/container"
+	"meowyplayer.com/utility/json"
+	"meowyplayer.com/utility/network"
+	"meowyplayer.com/utility/network/downloader"
+	"meowyplayer.com/utility/network/fileformat"
+	"meowyplayer.com/utility/network/httpclient"
+	"meowyplayer.com/utility/pattern"
+)
+
+const (
+	downloadRetries = 3
+	downloadBackoff = 2 * time.Second //doubled after every failed attempt
+)
+
+var downloadLock sync.Mutex
+var downloadJobs container.Slice[resource.DownloadJob]
+var downloadCancels = map[string]context.CancelFunc{}
+var downloadWaiters = map[string][]chan error{}
+var downloadCount atomic.Int64
+var downloadData pattern.Data[[]resource.DownloadJob]
+
+func GetDownloadData() *pattern.Data[[]resource.DownloadJob] {
+	return &downloadData
+}
+
+// load the persisted queue, the jobs interrupted by the last shutdown are queued again
+func LoadDownloads() error {
+	downloadLock.Lock()
+	defer downloadLock.Unlock()
+
+	jobs := container.Slice[resource.DownloadJob]{}
+	if err := json.ReadFile(resource.DownloadQueuePath(), &jobs); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i := range jobs {
+		if jobs[i].State == resource.DownloadRunning {
+			jobs[i].State = resource.DownloadQueued
+		}
+	}
+	downloadJobs = jobs
+	scheduleDownloadsLocked()
+	return publishDownloadsLocked(true)
+}
+
+// queue the video to be downloaded into the album, return the job id
+func QueueDownload(provider string, video *fileformat.VideoResult, album *resource.Album) (string, error) {
+	id, _, err := queueDownload(provider, video, album)
+	return id, err
+}
+
+// queue the video and wait for the download, cancelling the context cancels the job
+func Download(ctx context.Context, provider string, video *fileformat.VideoResult, album *resource.Album) error {
+	id, done, err := queueDownload(provider, video, album)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if err := CancelDownload(id); err != nil {
+			return err
+		}
+		return <-done
+	}
+}
+
+func queueDownload(provider string, video *fileformat.VideoResult, album *resource.Album) (string, chan error, error) {
+	album, err := getSourceAlbum(album)
+	if err != nil {
+		return "", nil, err
+	}
+
+	downloadLock.Lock()
+	defer downloadLock.Unlock()
+
+	job := resource.DownloadJob{
+		ID:         fmt.Sprintf("%v_%v", time.Now().UnixNano(), downloadCount.Add(1)),
+		Date:       time.Now(),
+		Provider:   provider,
+		Video:      *video,
+		AlbumTitle: album.Title,
+		State:      resource.DownloadQueued,
+		Total:      -1,
+	}
+	networkLog.Info("queued the download", "title", job.Video.Title, "album", job.AlbumTitle)
+
+	done := make(chan error, 1)
+	downloadWaiters[job.ID] = append(downloadWaiters[job.ID], done)
+	downloadJobs.PushBack(job)
+	scheduleDownloadsLocked()
+	return job.ID, done, publishDownloadsLocked(true)
+}
+
+// the queued jobs are dropped right away, the running ones stop at the next read
+func CancelDownload(id string) error {
+	downloadLock.Lock()
+	defer downloadLock.Unlock()
+
+	job := findDownloadLocked(id)
+	if job == nil {
+		return fmt.Errorf("%w: %v", resource.ErrDownloadNotFound, id)
+	}
+	switch job.State {
+	case resource.DownloadRunning:
+		downloadCancels[id]()
+	case resource.DownloadQueued:
+		job.State = resource.DownloadCancelled
+		finishDownloadLocked(id, context.Canceled)
+	}
+	return publishDownloadsLocked(true)
+}
+
+// queue the failed or cancelled job again, the partial file is resumed
+func RetryDownload(id string) error {
+	downloadLock.Lock()
+	defer downloadLock.Unlock()
+
+	job := findDownloadLocked(id)
+	if job == nil {
+		return fmt.Errorf("%w: %v", resource.ErrDownloadNotFound, id)
+	}
+	if job.State == resource.DownloadFailed || job.State == resource.DownloadCancelled {
+		job.State = resource.DownloadQueued
+		job.Error = ""
+		job.Attempts = 0
+		scheduleDownloadsLocked()
+	}
+	return publishDownloadsLocked(true)
+}
+
+// remove the finished jobs along with their partial files
+func RemoveDownloads(ids []string) error {
+	downloadLock.Lock()
+	defer downloadLock.Unlock()
+
+	downloadJobs = downloadJobs.Filter(func(j resource.DownloadJob) bool {
+		if j.Finished() && slices.Contains(ids, j.ID) {
+			os.Remove(resource.DownloadPartPath(&j))
+			return false
+		}
+		return true
+	})
+	return publishDownloadsLocked(true)
+}
+
+func ClearCompletedDownloads() error {
+	downloadLock.Lock()
+	defer downloadLock.Unlock()
+
+	downloadJobs = downloadJobs.Filter(func(j resource.DownloadJob) bool { return j.State != resource.DownloadCompleted })
+	return publishDownloadsLocked(true)
+}
+
+func scheduleDownloads() error {
+	downloadLock.Lock()
+	defer downloadLock.Unlock()
+	scheduleDownloadsLocked()
+	return publishDownloadsLocked(false)
+}
+
+// start the queued jobs in order until the concurrency limit is reached
+func scheduleDownloadsLocked() {
+	running := len(downloadJobs.Filter(func(j resource.DownloadJob) bool { return j.State == resource.DownloadRunning }))
+	for i := range downloadJobs {
+		if running >= max(configData.Get().Downloads, 1) {
+			return
+		}
+		if downloadJobs[i].State == resource.DownloadQueued {
+			ctx, cancel := context.WithCancel(context.Background())
+			downloadJobs[i].State = resource.DownloadRunning
+			downloadCancels[downloadJobs[i].ID] = cancel
+			go runDownload(ctx, downloadJobs[i])
+			running++
+		}
+	}
+}
+
+func runDownload(ctx context.Context, job resource.DownloadJob) {
+	networkLog.Info("downloading", "title", job.Video.Title, "album", job.AlbumTitle)
+	err := fetchDownload(ctx, &job)
+	if err == nil {
+		err = addDownloadedMusic(&job)
+	}
+
+	downloadLock.Lock()
+	defer downloadLock.Unlock()
+	downloadCancels[job.ID]()
+	delete(downloadCancels, job.ID)
+
+	if inQueue := findDownloadLocked(job.ID); inQueue != nil {
+		switch {
+		case err == nil:
+			inQueue.State = resource.DownloadCompleted
+		case ctx.Err() != nil:
+			inQueue.State = resource.DownloadCancelled
+			err = context.Canceled
+		default:
+			networkLog.Error("failed to download", "title", job.Video.Title, "err", err)
+			inQueue.State = resource.DownloadFailed
+			inQueue.Error = err.Error()
+		}
+		inQueue.Speed = 0
+	}
+	finishDownloadLocked(job.ID, err)
+	scheduleDownloadsLocked()
+	if err := publishDownloadsLocked(true); err != nil {
+		libraryLog.Error("failed to persist the download queue", "err", err)
+	}
+}
+
+// fetch the music into the partial file, retry with backoff
+func fetchDownload(ctx context.Context, job *resource.DownloadJob) error {
+	provider, err := network.Find(job.Provider)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		updateDownload(job.ID, true, func(j *resource.DownloadJob) { j.Attempts = attempt })
+		err := fetchDownloadOnce(ctx, &provider, job)
+		if err == nil || ctx.Err() != nil || attempt >= downloadRetries {
+			return err
+		}
+
+		backoff := downloadBackoff << (attempt - 1)
+		networkLog.Warn("retrying the download", "title", job.Video.Title, "backoff", backoff, "err", err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func fetchDownloadOnce(ctx context.Context, provider *network.Provider, job *resource.DownloadJob) error {
+	onProgress := func(p httpclient.Progress) {
+		updateDownload(job.ID, false, func(j *resource.DownloadJob) { j.Received, j.Total, j.Speed = p.Received, p.Total, p.Speed })
+	}
+	if fileDownloader, ok := provider.MusicDownloader.(downloader.FileDownloader); ok {
+		return fileDownloader.DownloadFile(ctx, &job.Video, resource.DownloadPartPath(job), onProgress)
+	}
+
+	//the downloaders without a stream are read in one go
+	resolver, ok := provider.MusicDownloader.(downloader.StreamResolver)
+	if !ok {
+		data, err := provider.MusicDownloader.Download(ctx, &job.Video)
+		if err != nil {
+			return err
+		}
+		updateDownload(job.ID, false, func(j *resource.DownloadJob) { j.Received, j.Total = int64(len(data)), int64(len(data)) })
+		return os.WriteFile(resource.DownloadPartPath(job), data, 0666)
+	}
+
+	stream, err := resolver.ResolveStream(ctx, &job.Video)
+	if err != nil {
+		return err
+	}
+	return httpclient.DownloadFile(ctx, stream.URL, stream.Header, resource.DownloadPartPath(job), onProgress)
+}
+
+func addDownloadedMusic(job *resource.DownloadJob) error {
+	if !albumExists(job.AlbumTitle) {
+		return fmt.Errorf("%w: %q", resource.ErrAlbumNotFound, job.AlbumTitle)
+	}
+	if err := AddMusicFromDownloader(&resource.Album{Title: job.AlbumTitle}, job.Provider, &job.Video, resource.DownloadPartPath(job)); err != nil {
+		return err
+	}
+	return os.Remove(resource.DownloadPartPath(job))
+}
+
+func updateDownload(id string, persist bool, update func(*resource.DownloadJob)) {
+	downloadLock.Lock()
+	defer downloadLock.Unlock()
+	if job := findDownloadLocked(id); job != nil {
+		update(job)
+		if err := publishDownloadsLocked(persist); err != nil {
+			libraryLog.Error("failed to persist the download queue", "err", err)
+		}
+	}
+}
+
+func findDownloadLocked(id string) *resource.DownloadJob {
+	index := slices.IndexFunc(downloadJobs, func(j resource.DownloadJob) bool { return j.ID == id })
+	if index == -1 {
+		return nil
+	}
+	return &downloadJobs[index]
+}
+
+func finishDownloadLocked(id string, err error) {
+	for _, done := range downloadWaiters[id] {
+		done <- err
+	}
+	delete(downloadWaiters, id)
+}
+
+// the progress updates are only displayed, the state changes are persisted as well
+func publishDownloadsLocked(persist bool) error {
+	downloadData.Set(slices.Clone(downloadJobs))
+	if persist {
+		return json.WriteFile(resource.DownloadQueuePath(), &downloadJobs)
+	}
+	return nil
+}
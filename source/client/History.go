@@ -0,0 +1,283 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"meowyplayer.com/source/resource"
+	"meowyplayer.com/utility/container"
+	"meowyplayer.com/utility/json"
+)
+
+const historyLimit = 32
+
+// every file side effect is recorded as a move, so that it can be reverted by moving it back
+type fileMove struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// a reversible library mutation, only the changed entries of the collection are kept
+type command struct {
+	Title    string                       `json:"title"`
+	Albums   listChange[resource.Album]   `json:"albums"`
+	Trash    listChange[resource.Trash]   `json:"trash"`
+	Stations listChange[resource.Station] `json:"stations"`
+	Moves    []fileMove                   `json:"moves"`
+
+	before *resource.Collection //the snapshot to diff against on commit
+}
+
+type history struct {
+	UndoList container.Slice[command] `json:"undoList"`
+	RedoList container.Slice[command] `json:"redoList"`
+}
+
+var commandHistory history
+var trashCount atomic.Int64
+
+func LoadHistory() error {
+	commandHistory = history{}
+	if err := json.ReadFile(resource.HistoryPath(), &commandHistory); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func newCommand(title string) *command {
+	before := cloneCollection(collectionData.Get())
+	return &command{Title: title, before: &before}
+}
+
+func (c *command) moveFile(from, to string) error {
+	if err := os.Rename(from, to); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	c.Moves = append(c.Moves, fileMove{from, to})
+	return nil
+}
+
+// move the file into the trash instead of removing it, return the trash path or empty if there is no such file
+func (c *command) trashFile(path string) (string, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "", nil
+	}
+	trashPath := newTrashPath(path)
+	return trashPath, c.moveFile(path, trashPath)
+}
+
+// stage the data in the trash and move it in place, the replaced file is trashed
+func (c *command) writeFile(path string, data []byte) error {
+	stagePath := newTrashPath(path)
+	if err := os.WriteFile(stagePath, data, os.ModePerm); err != nil {
+		return err
+	}
+	return c.placeFile(stagePath, path)
+}
+
+// move the staged file in place, the replaced file is trashed so that the undo brings it back
+func (c *command) placeFile(stagePath, path string) error {
+	if _, err := c.trashFile(path); err != nil {
+		os.Remove(stagePath)
+		return err
+	}
+	return c.moveFile(stagePath, path)
+}
+
+// a failed undo puts the files back, so that the library is never left half reverted
+func (c *command) undo() error {
+	return c.turn(reverseMoves(c.Moves), true)
+}
+
+func (c *command) redo() error {
+	return c.turn(c.Moves, false)
+}
+
+func (c *command) turn(moves []fileMove, undo bool) error {
+	if err := renameAll(moves); err != nil {
+		return err
+	}
+	if err := c.apply(undo); err != nil {
+		return errors.Join(err, renameAll(reverseMoves(moves)))
+	}
+	return nil
+}
+
+// the moves that put the files back, from the last one to the first
+func reverseMoves(moves []fileMove) []fileMove {
+	reverse := make([]fileMove, len(moves))
+	for i, move := range moves {
+		reverse[len(reverse)-1-i] = fileMove{move.To, move.From}
+	}
+	return reverse
+}
+
+// rename the files in order, the renamed ones are put back if any of them fails
+func renameAll(moves []fileMove) error {
+	for i, move := range moves {
+		if err := os.Rename(move.From, move.To); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				os.Rename(moves[j].To, moves[j].From)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// turn the changed entries over the current collection, so that the changes made outside of the history are kept
+func (c *command) apply(undo bool) error {
+	collection := cloneCollection(collectionData.Get())
+	collection.Albums = c.Albums.apply(collection.Albums, undo, albumKey, mergeAlbum)
+	collection.Trash = c.Trash.apply(collection.Trash, undo, trashKey, takeTarget[resource.Trash])
+	collection.Stations = c.Stations.apply(collection.Stations, undo, stationKey, takeTarget[resource.Station])
+	return setCollectionData(collection)
+}
+
+// remove the trashed files that can no longer be restored by the command, except the ones kept by the trash bin
+func (c *command) purge(undone bool) {
+	for _, move := range c.Moves {
+		path := move.To
+		if undone {
+			path = move.From
+		}
+		kept := slices.ContainsFunc(collectionData.Get().Trash, func(t resource.Trash) bool { return t.Cover == path })
+		if filepath.Dir(path) == resource.TrashPath() && !kept {
+			os.Remove(path)
+		}
+	}
+}
+
+// record the command into the history and persist the collection
+func commit(c *command) error {
+	after := collectionData.Get()
+	c.Albums = diffList(c.before.Albums, after.Albums, sameAlbum, albumKey)
+	c.Trash = diffList(c.before.Trash, after.Trash, sameEntry[resource.Trash], trashKey)
+	c.Stations = diffList(c.before.Stations, after.Stations, sameEntry[resource.Station], stationKey)
+	c.before = nil
+
+	for _, redo := range commandHistory.RedoList {
+		redo.purge(true)
+	}
+	commandHistory.RedoList.Clear()
+
+	commandHistory.UndoList.PushBack(*c)
+	if commandHistory.UndoList.Size() > historyLimit {
+		commandHistory.UndoList[0].purge(false)
+		commandHistory.UndoList.Remove(0)
+	}
+
+	if err := json.WriteFile(resource.HistoryPath(), &commandHistory); err != nil {
+		return err
+	}
+	return reloadCollectionData()
+}
+
+// revert the command that failed before its commit, the files are moved back and the collection is restored from the snapshot
+func (c *command) rollback() error {
+	if err := renameAll(reverseMoves(c.Moves)); err != nil {
+		return err
+	}
+	*collectionData.Get() = *c.before
+	c.purge(true)
+	return nil
+}
+
+// drop the commands that refer to the purged trash, since they can no longer be undone or redone
+// the older commands may have created or moved the purged files, so they are dropped as well, the newer ones are kept
+func forgetTrash(purged []resource.Trash) error {
+	keys := map[string]bool{}
+	for i := range purged {
+		keys[trashKey(&purged[i])] = true
+	}
+	isPurged := func(t resource.Trash) bool { return keys[trashKey(&t)] }
+	refersPurged := func(c *command) bool {
+		return slices.ContainsFunc(c.Trash.Before, isPurged) || slices.ContainsFunc(c.Trash.After, isPurged)
+	}
+
+	//the undo list starts from the oldest command and the redo list from the newest,
+	//so in both lists the commands up to the last one referring to the purged trash depend on it
+	forget := func(list container.Slice[command], undone bool) container.Slice[command] {
+		last := -1
+		for i := range list {
+			if refersPurged(&list[i]) {
+				last = i
+			}
+		}
+		for i := 0; i <= last; i++ {
+			list[i].purge(undone)
+		}
+		return slices.Clone(list[last+1:])
+	}
+	commandHistory = history{UndoList: forget(commandHistory.UndoList, false), RedoList: forget(commandHistory.RedoList, true)}
+	return json.WriteFile(resource.HistoryPath(), &commandHistory)
+}
+
+func Undo() error {
+	musicLock.Lock()
+	defer musicLock.Unlock()
+	if commandHistory.UndoList.Empty() {
+		return nil
+	}
+
+	c := *commandHistory.UndoList.Back()
+	if err := c.undo(); err != nil {
+		return fmt.Errorf("failed to undo \"%v\": %w", c.Title, err)
+	}
+	commandHistory.UndoList.PopBack()
+	commandHistory.RedoList.PushBack(c)
+
+	if err := json.WriteFile(resource.HistoryPath(), &commandHistory); err != nil {
+		return err
+	}
+	return reloadAlbumData()
+}
+
+func Redo() error {
+	musicLock.Lock()
+	defer musicLock.Unlock()
+	if commandHistory.RedoList.Empty() {
+		return nil
+	}
+
+	c := *commandHistory.RedoList.Back()
+	if err := c.redo(); err != nil {
+		return fmt.Errorf("failed to redo \"%v\": %w", c.Title, err)
+	}
+	commandHistory.RedoList.PopBack()
+	commandHistory.UndoList.PushBack(c)
+
+	if err := json.WriteFile(resource.HistoryPath(), &commandHistory); err != nil {
+		return err
+	}
+	return reloadAlbumData()
+}
+
+func newTrashPath(path string) string {
+	return filepath.Join(resource.TrashPath(), fmt.Sprintf("%v_%v_%v", time.Now().UnixNano(), trashCount.Add(1), filepath.Base(path)))
+}
+
+func cloneCollection(collection *resource.Collection) resource.Collection {
+	//the empty lists are kept nil, otherwise the diff takes them as changed
+	clone := *collection
+	clone.Albums = make(container.Slice[resource.Album], len(collection.Albums))
+	for i, album := range collection.Albums {
+		clone.Albums[i] = album
+		clone.Albums[i].MusicList = slices.Clone(album.MusicList)
+	}
+	clone.Trash = make(container.Slice[resource.Trash], len(collection.Trash))
+	for i, trash := range collection.Trash {
+		clone.Trash[i] = trash
+		clone.Trash[i].Album.MusicList = slices.Clone(trash.Album.MusicList)
+	}
+	clone.Stations = slices.Clone(collection.Stations)
+	return clone
+}
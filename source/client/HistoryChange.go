@@ -0,0 +1,135 @@
+package client
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+
+	"meowyplayer.com/source/resource"
+)
+
+/*
+The part of a list changed by a command, the common head and tail are left out.
+It is applied over the current list by the keys of the entries, so that the entries changed outside of the history,
+such as the refreshed podcasts and the episode positions, are kept as they are.
+*/
+type listChange[T any] struct {
+	Anchor string `json:"anchor,omitempty"` //the key of the entry right before the change, empty at the head
+	Before []T    `json:"before,omitempty"`
+	After  []T    `json:"after,omitempty"`
+}
+
+func diffList[T any](before, after []T, equal func(a, b *T) bool, key func(*T) string) listChange[T] {
+	head := 0
+	for head < len(before) && head < len(after) && equal(&before[head], &after[head]) {
+		head++
+	}
+	tail := 0
+	for tail < len(before)-head && tail < len(after)-head && equal(&before[len(before)-1-tail], &after[len(after)-1-tail]) {
+		tail++
+	}
+
+	change := listChange[T]{Before: slices.Clone(before[head : len(before)-tail]), After: slices.Clone(after[head : len(after)-tail])}
+	if head > 0 {
+		change.Anchor = key(&before[head-1])
+	}
+	return change
+}
+
+// turn the changed entries of the list back (undo) or forth, the entry still in the list is merged with its target
+func (c *listChange[T]) apply(list []T, undo bool, key func(*T) string, merge func(current, from, to *T) T) []T {
+	from, to := c.Before, c.After
+	if undo {
+		from, to = c.After, c.Before
+	}
+
+	//take out the changed entries
+	changed := map[string]bool{}
+	for i := range from {
+		changed[key(&from[i])] = true
+	}
+	for i := range to {
+		changed[key(&to[i])] = true
+	}
+	current := map[string]T{}
+	result := []T{}
+	for _, entry := range list {
+		if changed[key(&entry)] {
+			current[key(&entry)] = entry
+		} else {
+			result = append(result, entry)
+		}
+	}
+
+	//put the targets back after the anchor, the entry renamed in place is paired up by its position
+	at := 0
+	if c.Anchor != "" {
+		if at = slices.IndexFunc(result, func(entry T) bool { return key(&entry) == c.Anchor }) + 1; at == 0 {
+			at = len(result)
+		}
+	}
+	targets := make([]T, len(to))
+	for i := range to {
+		targets[i] = to[i]
+		index := slices.IndexFunc(from, func(entry T) bool { return key(&entry) == key(&to[i]) })
+		if index == -1 && len(from) == len(to) {
+			index = i
+		}
+		if index != -1 {
+			if entry, ok := current[key(&from[index])]; ok {
+				targets[i] = merge(&entry, &from[index], &to[i])
+			}
+		}
+	}
+	return slices.Insert(result, at, targets...)
+}
+
+func albumKey(album *resource.Album) string {
+	return album.Title
+}
+
+func musicKey(music *resource.Music) string {
+	return music.Title
+}
+
+func stationKey(station *resource.Station) string {
+	return station.Title
+}
+
+// the trash is never renamed, and its date is never changed
+func trashKey(trash *resource.Trash) string {
+	return strconv.FormatInt(trash.Date.UnixNano(), 10)
+}
+
+// the cover is loaded from the disk on every reload, it is not part of the change
+func sameAlbum(a, b *resource.Album) bool {
+	x, y := *a, *b
+	x.Cover, y.Cover = nil, nil
+	return reflect.DeepEqual(x, y)
+}
+
+func sameEntry[T any](a, b *T) bool {
+	return reflect.DeepEqual(*a, *b)
+}
+
+func takeTarget[T any](_, _, to *T) T {
+	return *to
+}
+
+// the feed is refreshed outside of the history, only the subscription itself is turned back
+func mergeAlbum(current, from, to *resource.Album) resource.Album {
+	album := *to
+	if current.Feed != nil && to.Feed != nil {
+		album.Feed = current.Feed
+	}
+	musicChange := diffList(from.MusicList, to.MusicList, sameEntry[resource.Music], musicKey)
+	album.MusicList = musicChange.apply(current.MusicList, false, musicKey, mergeMusic)
+	return album
+}
+
+// the playback states are saved outside of the history
+func mergeMusic(current, _, to *resource.Music) resource.Music {
+	music := *to
+	music.Unplayable, music.Played, music.Position = current.Unplayable, current.Played, current.Position
+	return music
+}
@@ -1,26 +1,47 @@
 package client
 
 import (
+	"fmt"
 	"slices"
 
 	"meowyplayer.com/source/resource"
 	"meowyplayer.com/utility/json"
+	"meowyplayer.com/utility/logger"
 	"meowyplayer.com/utility/pattern"
 )
 
+var playerLog = logger.Subsystem(logger.Player)
+var networkLog = logger.Subsystem(logger.Network)
+var libraryLog = logger.Subsystem(logger.Library)
+
 var collectionData pattern.Data[*resource.Collection]
 var albumData pattern.Data[*resource.Album]
+var albumRefreshData pattern.Data[*resource.Album]
 var playListData pattern.Data[*resource.PlayList]
+var queueData pattern.Data[[]resource.Music]
+var stationData pattern.Data[*resource.Station]
+var playbackErrorData pattern.Data[error]
 
 // the album pointer parameter may refer to a temporary object from the view list
 // we need the original one from the collection
-func getSourceAlbum(album *resource.Album) *resource.Album {
+func getSourceAlbum(album *resource.Album) (*resource.Album, error) {
+	if album == nil {
+		return nil, fmt.Errorf("%w: no album is selected", resource.ErrAlbumNotFound)
+	}
 	index := slices.IndexFunc(collectionData.Get().Albums, func(a resource.Album) bool { return a.Title == album.Title })
-	return &collectionData.Get().Albums[index]
+	if index == -1 {
+		return nil, fmt.Errorf("%w: %q", resource.ErrAlbumNotFound, album.Title)
+	}
+	return &collectionData.Get().Albums[index], nil
 }
 
 func reloadCollectionData() error {
-	if err := json.WriteFile(resource.CollectionPath(), collectionData.Get()); err != nil {
+	return setCollectionData(*collectionData.Get())
+}
+
+// persist the collection and reload it from the disk
+func setCollectionData(inUse resource.Collection) error {
+	if err := json.WriteFile(resource.CollectionPath(), &inUse); err != nil {
 		return err
 	}
 	collection, err := LoadFromLocalCollection()
@@ -32,10 +53,40 @@ func reloadCollectionData() error {
 }
 
 func reloadAlbumData() error {
-	albumData.Set(getSourceAlbum(albumData.Get()))
+	//no album has been selected yet, or the album no longer exists
+	album, err := getSourceAlbum(albumData.Get())
+	if err != nil {
+		return nil
+	}
+	albumData.Set(album)
+	return nil
+}
+
+// refresh the views of the current album after a background change, unlike reloadAlbumData it is not a new selection
+func refreshAlbumData() error {
+	album, err := getSourceAlbum(albumData.Get())
+	if err != nil {
+		return nil
+	}
+	albumRefreshData.Set(album)
 	return nil
 }
 
+// whether the video has been downloaded into any album, and into the current album
+func FindDownloadedVideo(videoID string) (inLibrary bool, inAlbum bool) {
+	downloaded := func(m resource.Music) bool { return m.Source != nil && m.Source.VideoID == videoID }
+	if videoID == "" || collectionData.Get() == nil {
+		return false, false
+	}
+	for _, album := range collectionData.Get().Albums {
+		if slices.ContainsFunc(album.MusicList, downloaded) {
+			inLibrary = true
+			inAlbum = inAlbum || (albumData.Get() != nil && album.Title == albumData.Get().Title)
+		}
+	}
+	return inLibrary, inAlbum
+}
+
 func GetCollectionData() *pattern.Data[*resource.Collection] {
 	return &collectionData
 }
@@ -44,10 +95,28 @@ func GetAlbumData() *pattern.Data[*resource.Album] {
 	return &albumData
 }
 
+// the current album changed in the background, such as the refreshed podcast
+func GetAlbumRefreshData() *pattern.Data[*resource.Album] {
+	return &albumRefreshData
+}
+
 func GetPlayListData() *pattern.Data[*resource.PlayList] {
 	return &playListData
 }
 
+func GetQueueData() *pattern.Data[[]resource.Music] {
+	return &queueData
+}
+
+func GetStationData() *pattern.Data[*resource.Station] {
+	return &stationData
+}
+
+// the music that failed to play, set by the player
+func GetPlaybackErrorData() *pattern.Data[error] {
+	return &playbackErrorData
+}
+
 func LoadFromLocalCollection() (resource.Collection, error) {
 	inUse := resource.Collection{}
 	if err := json.ReadFile(resource.CollectionPath(), &inUse); err != nil {
@@ -0,0 +1,337 @@
+package client_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"meowyplayer.com/source/client"
+	"meowyplayer.com/source/resource"
+	"meowyplayer.com/utility/cleaner"
+	"meowyplayer.com/utility/network"
+)
+
+// start from an empty library in a temporary directory, the library paths are relative to the working directory
+func newTestLibrary(t *testing.T) {
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	t.Cleanup(func() { os.Chdir(dir) })
+
+	if err := resource.MakeNecessaryPath(); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if err := client.LoadConfig(); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if err := client.LoadHistory(); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	collection, err := client.LoadFromLocalCollection()
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	client.GetCollectionData().Set(&collection)
+	client.GetAlbumData().Set(nil)
+}
+
+// add an album and return it as it is in the collection
+func addTestAlbum(t *testing.T) resource.Album {
+	if err := client.AddAlbum(); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	albums := client.GetCollectionData().Get().Albums
+	return albums[len(albums)-1]
+}
+
+func TestAlbumNotFound(t *testing.T) {
+	newTestLibrary(t)
+	missing := resource.Album{Title: "Missing"}
+
+	if err := client.DeleteAlbum(&missing); !errors.Is(err, resource.ErrAlbumNotFound) {
+		t.Errorf("expected %v from deleting, got %v\n", resource.ErrAlbumNotFound, err)
+	}
+	if err := client.UpdateAlbumTitle(&missing, "Renamed"); !errors.Is(err, resource.ErrAlbumNotFound) {
+		t.Errorf("expected %v from renaming, got %v\n", resource.ErrAlbumNotFound, err)
+	}
+	if err := client.CopyMusic(nil, &missing); !errors.Is(err, resource.ErrAlbumNotFound) {
+		t.Errorf("expected %v from copying without the current album, got %v\n", resource.ErrAlbumNotFound, err)
+	}
+	if err := client.DeleteMusic(nil); !errors.Is(err, resource.ErrAlbumNotFound) {
+		t.Errorf("expected %v from deleting without the current album, got %v\n", resource.ErrAlbumNotFound, err)
+	}
+}
+
+func TestAlbumDuplicateTitle(t *testing.T) {
+	newTestLibrary(t)
+	first, second := addTestAlbum(t), addTestAlbum(t)
+
+	if err := client.UpdateAlbumTitle(&first, second.Title); !errors.Is(err, resource.ErrDuplicateTitle) {
+		t.Errorf("expected %v, got %v\n", resource.ErrDuplicateTitle, err)
+	}
+	if albums := client.GetCollectionData().Get().Albums; albums[0].Title != first.Title {
+		t.Errorf("expected the album to keep its title, got %v\n", albums[0].Title)
+	}
+}
+
+func TestTrackNotFound(t *testing.T) {
+	newTestLibrary(t)
+	album := addTestAlbum(t)
+	client.GetAlbumData().Set(&album)
+
+	music, target := resource.Music{Title: "missing.mp3"}, resource.Music{Title: "target.mp3"}
+	if err := client.ReorderMusic(&music, &target); !errors.Is(err, resource.ErrTrackNotFound) {
+		t.Errorf("expected %v, got %v\n", resource.ErrTrackNotFound, err)
+	}
+}
+
+func TestStationErrors(t *testing.T) {
+	newTestLibrary(t)
+	if err := client.AddStation("Meowy FM", "http://localhost/stream"); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if err := client.AddStation("Meowy FM", "http://localhost/other"); !errors.Is(err, resource.ErrDuplicateTitle) {
+		t.Errorf("expected %v from adding, got %v\n", resource.ErrDuplicateTitle, err)
+	}
+	station := client.GetCollectionData().Get().Stations[0]
+	if err := client.UpdateStationTitle(&resource.Station{Title: "Missing"}, "Renamed"); !errors.Is(err, resource.ErrStationNotFound) {
+		t.Errorf("expected %v from renaming, got %v\n", resource.ErrStationNotFound, err)
+	}
+	if err := client.UpdateStationTitle(&resource.Station{Title: "Missing"}, station.Title); !errors.Is(err, resource.ErrDuplicateTitle) {
+		t.Errorf("expected %v from renaming to a taken title, got %v\n", resource.ErrDuplicateTitle, err)
+	}
+	if err := client.DeleteStation(&resource.Station{Title: "Missing"}); !errors.Is(err, resource.ErrStationNotFound) {
+		t.Errorf("expected %v from deleting, got %v\n", resource.ErrStationNotFound, err)
+	}
+}
+
+func TestTrashNotFound(t *testing.T) {
+	newTestLibrary(t)
+	trash := resource.Trash{Date: time.Now(), IsAlbum: true, Album: resource.Album{Title: "Missing"}}
+
+	if err := client.RestoreTrash(&trash); !errors.Is(err, resource.ErrTrashNotFound) {
+		t.Errorf("expected %v from restoring, got %v\n", resource.ErrTrashNotFound, err)
+	}
+	if err := client.PurgeTrash(&trash); !errors.Is(err, resource.ErrTrashNotFound) {
+		t.Errorf("expected %v from purging, got %v\n", resource.ErrTrashNotFound, err)
+	}
+}
+
+func TestDownloadNotFound(t *testing.T) {
+	newTestLibrary(t)
+	if err := client.CancelDownload("missing"); !errors.Is(err, resource.ErrDownloadNotFound) {
+		t.Errorf("expected %v from cancelling, got %v\n", resource.ErrDownloadNotFound, err)
+	}
+	if err := client.RetryDownload("missing"); !errors.Is(err, resource.ErrDownloadNotFound) {
+		t.Errorf("expected %v from retrying, got %v\n", resource.ErrDownloadNotFound, err)
+	}
+}
+
+func TestProviderNotFound(t *testing.T) {
+	newTestLibrary(t)
+	if err := client.SetProviderEnabled("Missing", true); !errors.Is(err, network.ErrProviderNotFound) {
+		t.Errorf("expected %v from enabling, got %v\n", network.ErrProviderNotFound, err)
+	}
+	if err := client.MoveProvider("Missing", 1); !errors.Is(err, network.ErrProviderNotFound) {
+		t.Errorf("expected %v from moving, got %v\n", network.ErrProviderNotFound, err)
+	}
+}
+
+func albumTitles() []string {
+	titles := []string{}
+	for _, album := range client.GetCollectionData().Get().Albums {
+		titles = append(titles, album.Title)
+	}
+	return titles
+}
+
+func TestUndoRedo(t *testing.T) {
+	newTestLibrary(t)
+	first, second := addTestAlbum(t), addTestAlbum(t)
+	if err := client.UpdateAlbumTitle(&first, "Renamed"); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if err := client.DeleteAlbum(&second); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	steps := []struct {
+		step     func() error
+		expected []string
+	}{
+		{client.Undo, []string{"Renamed", second.Title}},
+		{client.Undo, []string{first.Title, second.Title}},
+		{client.Redo, []string{"Renamed", second.Title}},
+		{client.Redo, []string{"Renamed"}},
+	}
+	for i, step := range steps {
+		if err := step.step(); err != nil {
+			t.Fatalf("%v\n", err)
+		}
+		if titles := albumTitles(); !slices.Equal(titles, step.expected) {
+			t.Errorf("step %v: expected %v, got %v\n", i, step.expected, titles)
+		}
+	}
+	if trash := client.GetCollectionData().Get().Trash; len(trash) != 1 || trash[0].Album.Title != second.Title {
+		t.Errorf("expected the deleted album in the trash, got %v\n", trash)
+	}
+}
+
+func TestPurgeKeepsHistory(t *testing.T) {
+	newTestLibrary(t)
+	first, second := addTestAlbum(t), addTestAlbum(t)
+	if err := client.DeleteAlbum(&second); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if err := client.UpdateAlbumTitle(&first, "Renamed"); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if err := client.PurgeTrash(&client.GetCollectionData().Get().Trash[0]); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	//the rename is still undoable, but not the purged deletion
+	if err := client.Undo(); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if titles := albumTitles(); !slices.Equal(titles, []string{first.Title}) {
+		t.Errorf("expected the rename to be undone, got %v\n", titles)
+	}
+	if err := client.Undo(); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if titles := albumTitles(); slices.Contains(titles, second.Title) {
+		t.Errorf("expected the purged album to stay purged, got %v\n", titles)
+	}
+}
+
+// write frames of silence, a 128 kbps frame at 44.1 kHz is 417 bytes
+func writeSilentMP3(t *testing.T, path string, frames int) {
+	frame := make([]byte, 417)
+	copy(frame, []byte{0xFF, 0xFB, 0x90, 0x00})
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if err := os.WriteFile(path, bytes.Repeat(frame, frames), 0666); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+}
+
+func TestUndoReplacedMusic(t *testing.T) {
+	newTestLibrary(t)
+	album := addTestAlbum(t)
+	older, newer := filepath.Join(t.TempDir(), "older", "song.mp3"), filepath.Join(t.TempDir(), "newer", "song.mp3")
+	writeSilentMP3(t, older, 10)
+	writeSilentMP3(t, newer, 20)
+
+	for _, path := range []string{older, newer} {
+		if err := client.AddMusicFromPaths(&album, []string{path}); err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}
+	musicPath := resource.MusicPath(&resource.Music{Title: "song.mp3"})
+	if info, err := os.Stat(musicPath); err != nil || info.Size() != 20*417 {
+		t.Fatalf("expected the newer music to replace the older one, got %v, %v\n", info, err)
+	}
+
+	if err := client.Undo(); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if info, err := os.Stat(musicPath); err != nil || info.Size() != 10*417 {
+		t.Errorf("expected the older music to be restored, got %v, %v\n", info, err)
+	}
+}
+
+func TestAddFoldersRollback(t *testing.T) {
+	newTestLibrary(t)
+	folder := filepath.Join(t.TempDir(), "folder")
+	writeSilentMP3(t, filepath.Join(folder, "song.mp3"), 10)
+
+	err := client.AddAlbumsFromFolders([]string{folder, filepath.Join(t.TempDir(), "missing")})
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected %v, got %v\n", os.ErrNotExist, err)
+	}
+	if titles := albumTitles(); len(titles) != 0 {
+		t.Errorf("expected the added folder to be rolled back, got %v\n", titles)
+	}
+	if _, err := os.Stat(resource.MusicPath(&resource.Music{Title: "song.mp3"})); !os.IsNotExist(err) {
+		t.Errorf("expected the music file to be moved out, got %v\n", err)
+	}
+	if entries, err := os.ReadDir(resource.TrashPath()); err != nil || len(entries) != 0 {
+		t.Errorf("expected no staged file to be left in the trash, got %v, %v\n", entries, err)
+	}
+}
+
+func TestUndoPutsBackFiles(t *testing.T) {
+	newTestLibrary(t)
+	album := addTestAlbum(t)
+	first, second := filepath.Join(t.TempDir(), "first.mp3"), filepath.Join(t.TempDir(), "second.mp3")
+	writeSilentMP3(t, first, 10)
+	writeSilentMP3(t, second, 10)
+	if err := client.AddMusicFromPaths(&album, []string{first, second}); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	//the second music is moved out first, then the missing first music fails the undo
+	firstPath, secondPath := resource.MusicPath(&resource.Music{Title: "first.mp3"}), resource.MusicPath(&resource.Music{Title: "second.mp3"})
+	if err := os.Remove(firstPath); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if err := client.Undo(); err == nil {
+		t.Fatalf("expected the undo to fail\n")
+	}
+	if _, err := os.Stat(secondPath); err != nil {
+		t.Errorf("expected the second music to be put back, got %v\n", err)
+	}
+	if music := client.GetCollectionData().Get().Albums[0].MusicList; len(music) != 2 {
+		t.Errorf("expected the album to keep its music, got %v\n", music)
+	}
+}
+
+func TestAddMusicRollback(t *testing.T) {
+	newTestLibrary(t)
+	album := addTestAlbum(t)
+	valid, broken := filepath.Join(t.TempDir(), "valid.mp3"), filepath.Join(t.TempDir(), "broken.mp3")
+	writeSilentMP3(t, valid, 10)
+	if err := os.WriteFile(broken, []byte("not an mp3"), 0666); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if err := client.AddMusicFromPaths(&album, []string{valid, broken}); err == nil {
+		t.Fatalf("expected the broken music to fail\n")
+	}
+	if music := client.GetCollectionData().Get().Albums[0].MusicList; len(music) != 0 {
+		t.Errorf("expected the added music to be rolled back, got %v\n", music)
+	}
+	if _, err := os.Stat(resource.MusicPath(&resource.Music{Title: "valid.mp3"})); !os.IsNotExist(err) {
+		t.Errorf("expected the music file to be moved out, got %v\n", err)
+	}
+}
+
+func TestLoadInvalidConfig(t *testing.T) {
+	newTestLibrary(t)
+	config := `{"network": {"proxy": "://invalid"}, "titleRules": {"removals": ["(unclosed"]}, "downloads": 5}`
+	if err := os.WriteFile(resource.ConfigPath(), []byte(config), 0666); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if err := client.LoadConfig(); !errors.Is(err, resource.ErrInvalidConfig) {
+		t.Fatalf("expected %v, got %v\n", resource.ErrInvalidConfig, err)
+	}
+	loaded := client.GetConfigData().Get()
+	if loaded.Network.Proxy != "" || !slices.Equal(loaded.TitleRules.Removals, cleaner.DefaultRules().Removals) {
+		t.Errorf("expected the invalid settings to fall back to the defaults, got %+v\n", loaded)
+	}
+	if loaded.Downloads != 5 {
+		t.Errorf("expected the valid settings to be kept, got %v\n", loaded.Downloads)
+	}
+}
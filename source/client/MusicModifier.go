@@ -1,8 +1,13 @@
 package client
 
 import (
-	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"path/filepath"
 	"slices"
 	"strings"
 	"sync"
@@ -11,83 +16,418 @@ import (
 	"fyne.io/fyne/v2"
 	"github.com/hajimehoshi/go-mp3"
 	"meowyplayer.com/source/resource"
-	"meowyplayer.com/utility/assert"
+	"meowyplayer.com/utility/audio"
+	"meowyplayer.com/utility/cleaner"
+	"meowyplayer.com/utility/id3"
 	"meowyplayer.com/utility/network/fileformat"
 )
 
 var musicLock sync.Mutex
 
-func addMusic(music resource.Music, musicData []byte) error {
+// the bitrate of the mp3 converted from the other formats, in kbps
+const transcodeBitrate = 192
+
+// move the staged file into the music repo and add it to the album, the music of the same title is replaced
+func addMusic(album *resource.Album, music resource.Music, stagePath string) error {
 	musicLock.Lock()
 	defer musicLock.Unlock()
-
-	//write data to the music repo
-	if err := os.WriteFile(resource.MusicPath(&music), musicData, 0777); err != nil {
+	album, err := getSourceAlbum(album)
+	if err != nil {
+		os.Remove(stagePath)
 		return err
 	}
+	command := newCommand(fmt.Sprintf("add %v", music.Title))
+	if err := command.placeFile(stagePath, resource.MusicPath(&music)); err != nil {
+		return errors.Join(err, command.rollback())
+	}
 
 	//add the music info to the album
-	album := getSourceAlbum(albumData.Get())
-	if !slices.ContainsFunc(album.MusicList, func(m resource.Music) bool { return m.Title == music.Title }) {
+	if !containsMusic(album.MusicList, &music) {
 		album.MusicList.PushBack(music)
 	}
-	if err := reloadCollectionData(); err != nil {
+	if err := commit(command); err != nil {
 		return err
 	}
 	return reloadAlbumData()
 }
 
-func AddMusicFromDownloader(videoResult *fileformat.VideoResult, musicData []byte) error {
-	//sanitize music title
-	sanitizer := strings.NewReplacer(
-		"<", "",
-		">", "",
-		":", "",
-		"\"", "",
-		"/", "",
-		"\\", "",
-		"|", "",
-		"?", "",
-		"*", "",
-	)
-	music := resource.Music{Date: time.Now(), Title: sanitizer.Replace(videoResult.Title) + ".mp3", Length: videoResult.Length}
-	return addMusic(music, musicData)
+// add the downloaded file to the album, it is streamed through the tagging instead of being read into the memory
+func AddMusicFromDownloader(album *resource.Album, provider string, videoResult *fileformat.VideoResult, musicPath string) error {
+	//clean up the title by the rules, then sanitize it into a file name
+	cleaned := CleanTitle(videoResult.Title)
+	music := resource.Music{Date: time.Now(), Title: titleSanitizer.Replace(cleaned.Title) + ".mp3", Length: videoResult.Length, Artist: cleaned.Artist}
+	music.Source = &resource.Source{
+		Provider:     provider,
+		VideoID:      videoResult.VideoID,
+		URL:          videoResult.URL,
+		AudioURL:     videoResult.AudioURL,
+		ChannelID:    videoResult.ChannelID,
+		ChannelTitle: videoResult.ChannelTitle,
+		Description:  videoResult.Description,
+		Date:         music.Date,
+	}
+
+	//some downloaders deliver the audio as an mp4 container (aac), which the player can't decode
+	isMP4, err := isMP4File(musicPath)
+	if err != nil {
+		return err
+	}
+	if isMP4 {
+		converted := musicPath + ".mp3"
+		if err := audio.TranscodeToMP3(context.Background(), musicPath, converted, transcodeBitrate); err != nil {
+			os.Remove(converted)
+			return err
+		}
+		defer os.Remove(converted)
+		musicPath = converted
+	}
+
+	//some scrapers don't know the video length
+	if music.Length == 0 {
+		mp3File, err := audio.OpenMP3(musicPath)
+		if err != nil {
+			return err
+		}
+		music.Length = mp3Duration(mp3File.Decoder)
+		mp3File.Close()
+	}
+
+	stagePath := newTrashPath(resource.MusicPath(&music))
+	if err := tagMP3File(&cleaned, videoResult, musicPath, stagePath); err != nil {
+		os.Remove(stagePath)
+		return err
+	}
+	return addMusic(album, music, stagePath)
+}
+
+func isMP4File(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+	header := make([]byte, 8)
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return false, err
+	}
+	return audio.IsMP4(header[:n]), nil
+}
+
+// describe the source in the mp3 file itself, so that it makes sense outside of the player
+// the artist read from the title is preferred over the channel
+func tagMP3File(cleaned *cleaner.Result, videoResult *fileformat.VideoResult, musicPath string, taggedPath string) error {
+	tag := id3.Tag{Title: cleaned.Title, Artist: cleaned.Artist, URL: videoResult.URL}
+	if tag.Artist == "" {
+		tag.Artist = videoResult.ChannelTitle
+	}
+
+	//the music is still tagged without its cover
+	if videoResult.ThumbnailURL != "" {
+		if thumbnail, err := thumbnailLoader.Fetch(context.Background(), videoResult.ThumbnailURL); err != nil {
+			networkLog.Warn("failed to fetch the cover", "title", videoResult.Title, "err", err)
+		} else {
+			tag.Picture = thumbnail.Content()
+			tag.PictureMIME = http.DetectContentType(tag.Picture)
+		}
+	}
+
+	source, err := os.Open(musicPath)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	tagged, err := os.OpenFile(taggedPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0777)
+	if err != nil {
+		return err
+	}
+	if err := id3.Copy(tagged, &tag, source); err != nil {
+		tagged.Close()
+		return err
+	}
+	return tagged.Close()
 }
 
 func AddMusicFromURIReader(musicInfo fyne.URIReadCloser) error {
-	estimateMP3DataLength := func(data []byte) (time.Duration, error) {
-		decoder, err := mp3.NewDecoder(bytes.NewReader(data))
+	defer musicInfo.Close()
+	return AddMusicFromPaths(albumData.Get(), []string{musicInfo.URI().Path()})
+}
+
+// add the music files (or the music files inside the folders) to the album in one go
+func AddMusicFromPaths(album *resource.Album, paths []string) error {
+	musicLock.Lock()
+	defer musicLock.Unlock()
+
+	source, err := getSourceAlbum(album)
+	if err != nil {
+		return err
+	}
+	command := newCommand(fmt.Sprintf("add %v items to %v", len(paths), album.Title))
+	if err := addMusicFromPaths(command, source, paths); err != nil {
+		return errors.Join(err, command.rollback())
+	}
+	if err := commit(command); err != nil {
+		return err
+	}
+	return reloadAlbumData()
+}
+
+func addMusicFromPaths(command *command, album *resource.Album, paths []string) error {
+	musicPaths, err := collectMusicPaths(paths)
+	if err != nil {
+		return err
+	}
+
+	for _, musicPath := range musicPaths {
+		music, err := copyMusicFile(command, musicPath)
+		if err != nil {
+			return err
+		}
+		if !containsMusic(album.MusicList, &music) {
+			album.MusicList.PushBack(music)
+		}
+	}
+	return nil
+}
+
+// expand the folders and keep the mp3 files only
+func collectMusicPaths(paths []string) ([]string, error) {
+	musicPaths := []string{}
+	for _, path := range paths {
+		info, err := os.Stat(path)
 		if err != nil {
-			return 0, err
+			return nil, err
 		}
-		seconds := float64(decoder.Length()) / float64(resource.SAMPLING_RATE) / float64(resource.NUM_OF_CHANNELS) / float64(resource.AUDIO_BIT_DEPTH)
-		return time.Duration(seconds * float64(time.Second)), nil
+
+		if !info.IsDir() {
+			if strings.EqualFold(filepath.Ext(path), ".mp3") {
+				musicPaths = append(musicPaths, path)
+			}
+			continue
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.EqualFold(filepath.Ext(entry.Name()), ".mp3") {
+				musicPaths = append(musicPaths, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+	return musicPaths, nil
+}
+
+// copy the file into the music repo, without holding all of it in the memory
+// it is staged in the trash first, so that the replaced music of the same title can be restored
+func copyMusicFile(command *command, musicPath string) (resource.Music, error) {
+	mp3File, err := audio.OpenMP3(musicPath)
+	if err != nil {
+		return resource.Music{}, err
 	}
+	music := resource.Music{Date: time.Now(), Title: filepath.Base(musicPath), Length: mp3Duration(mp3File.Decoder)}
+	mp3File.Close()
+
+	source, err := os.Open(musicPath)
+	if err != nil {
+		return resource.Music{}, err
+	}
+	defer source.Close()
+	stagePath := newTrashPath(resource.MusicPath(&music))
+	destination, err := os.OpenFile(stagePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0777)
+	if err != nil {
+		return resource.Music{}, err
+	}
+	if _, err := io.Copy(destination, source); err != nil {
+		destination.Close()
+		os.Remove(stagePath)
+		return resource.Music{}, err
+	}
+	if err := destination.Close(); err != nil {
+		os.Remove(stagePath)
+		return resource.Music{}, err
+	}
+	return music, command.placeFile(stagePath, resource.MusicPath(&music))
+}
 
-	data, err := os.ReadFile(musicInfo.URI().Path())
+func mp3Duration(decoder *mp3.Decoder) time.Duration {
+	seconds := float64(decoder.Length()) / float64(resource.SAMPLING_RATE) / float64(resource.NUM_OF_CHANNELS) / float64(resource.AUDIO_BIT_DEPTH)
+	return time.Duration(seconds * float64(time.Second))
+}
+
+func DeleteMusic(musicList []resource.Music) error {
+	musicLock.Lock()
+	defer musicLock.Unlock()
+	album, err := getSourceAlbum(albumData.Get())
 	if err != nil {
 		return err
 	}
-	length, err := estimateMP3DataLength(data)
+	command := newCommand(fmt.Sprintf("delete %v music", len(musicList)))
+
+	//move into the trash bin, the music file stays in the music repo until the trash is purged
+	trash := resource.Trash{Date: time.Now(), Album: resource.Album{Date: album.Date, Title: album.Title}}
+	trash.Album.MusicList = album.MusicList.Filter(func(m resource.Music) bool { return containsMusic(musicList, &m) })
+	collectionData.Get().Trash.PushBack(trash)
+	album.MusicList = album.MusicList.Filter(func(m resource.Music) bool { return !containsMusic(musicList, &m) })
+
+	if err := commit(command); err != nil {
+		return err
+	}
+	return reloadAlbumData()
+}
+
+// move the music to the position of the target in the current album
+func ReorderMusic(music *resource.Music, target *resource.Music) error {
+	musicLock.Lock()
+	defer musicLock.Unlock()
+	album, err := getSourceAlbum(albumData.Get())
 	if err != nil {
 		return err
 	}
-	music := resource.Music{Date: time.Now(), Title: musicInfo.URI().Name(), Length: length}
-	return addMusic(music, data)
+
+	from := slices.IndexFunc(album.MusicList, func(m resource.Music) bool { return m.Title == music.Title })
+	if from == -1 {
+		return fmt.Errorf("%w: %q in %q", resource.ErrTrackNotFound, music.Title, album.Title)
+	}
+	to := slices.IndexFunc(album.MusicList, func(m resource.Music) bool { return m.Title == target.Title })
+	if to == -1 {
+		return fmt.Errorf("%w: %q in %q", resource.ErrTrackNotFound, target.Title, album.Title)
+	}
+	command := newCommand(fmt.Sprintf("move %v", music.Title))
+	album.MusicList.Move(from, to)
+
+	if err := commit(command); err != nil {
+		return err
+	}
+	return reloadAlbumData()
 }
 
-func DeleteMusic(music *resource.Music) error {
+// move the music from the current album to the destination album
+func MoveMusic(musicList []resource.Music, album *resource.Album) error {
+	return transferMusic(musicList, album, true)
+}
+
+// copy the music from the current album to the destination album
+func CopyMusic(musicList []resource.Music, album *resource.Album) error {
+	return transferMusic(musicList, album, false)
+}
+
+func transferMusic(musicList []resource.Music, album *resource.Album, removeSource bool) error {
 	musicLock.Lock()
 	defer musicLock.Unlock()
-	album := getSourceAlbum(albumData.Get())
+	source, err := getSourceAlbum(albumData.Get())
+	if err != nil {
+		return err
+	}
+	destination, err := getSourceAlbum(album)
+	if err != nil {
+		return err
+	}
+	if source == destination {
+		return nil
+	}
+	command := newCommand(fmt.Sprintf("transfer %v music to %v", len(musicList), destination.Title))
 
-	//remove from the collection, but not delete it from the music repo
-	index := slices.IndexFunc(album.MusicList, func(m resource.Music) bool { return m.SimpleTitle() == music.SimpleTitle() })
-	assert.Ensure(func() bool { return index != -1 })
-	album.MusicList.Remove(index)
+	for _, music := range musicList {
+		if !containsMusic(destination.MusicList, &music) {
+			destination.MusicList.PushBack(music)
+		}
+	}
+	if removeSource {
+		source.MusicList = source.MusicList.Filter(func(m resource.Music) bool { return !containsMusic(musicList, &m) })
+	}
 
-	if err := reloadCollectionData(); err != nil {
+	if err := commit(command); err != nil {
 		return err
 	}
 	return reloadAlbumData()
 }
+
+/*
+Mark whether the music file failed to play, in every album sharing the file.
+It is not a user change, so it is saved without an undo entry, and nothing is saved if the mark is unchanged.
+*/
+func setMusicUnplayable(music *resource.Music, unplayable bool) error {
+	musicLock.Lock()
+	defer musicLock.Unlock()
+
+	changed := false
+	for i := range collectionData.Get().Albums {
+		musicList := collectionData.Get().Albums[i].MusicList
+		for j := range musicList {
+			if musicList[j].Title == music.Title && musicList[j].Unplayable != unplayable {
+				musicList[j].Unplayable = unplayable
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if err := reloadCollectionData(); err != nil {
+		return err
+	}
+	return refreshAlbumData()
+}
+
+// queue the downloaded music again into the album, the files are replaced once downloaded
+// the local music can't be downloaded and is skipped
+func RedownloadMusic(album *resource.Album, musicList []resource.Music) error {
+	for _, music := range musicList {
+		if music.Source == nil {
+			continue
+		}
+		video := fileformat.VideoResult{
+			VideoID:      music.Source.VideoID,
+			URL:          music.Source.URL,
+			ChannelID:    music.Source.ChannelID,
+			ChannelTitle: music.Source.ChannelTitle,
+			Title:        music.SimpleTitle(),
+			Description:  music.Source.Description,
+			Length:       music.Length,
+			AudioURL:     music.Source.AudioURL,
+		}
+		if _, err := QueueDownload(music.Source.Provider, &video, album); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collect the music downloaded from the channel, grouped by the albums holding them
+func FindMusicByChannel(channelID string) []resource.Album {
+	albums := []resource.Album{}
+	for _, album := range collectionData.Get().Albums {
+		musicList := album.MusicList.Filter(func(m resource.Music) bool { return m.Source != nil && m.Source.ChannelID == channelID })
+		if len(musicList) > 0 {
+			albums = append(albums, resource.Album{Date: album.Date, Title: album.Title, MusicList: musicList})
+		}
+	}
+	return albums
+}
+
+// the music queued but not yet taken by the player, in the order it is queued
+var queuedMusic []resource.Music
+var queueLock sync.Mutex
+
+// append the music to the play list that is currently playing
+// the notification only wakes the player up, which takes the queued music in order
+func QueueMusic(musicList []resource.Music) {
+	queueLock.Lock()
+	queuedMusic = append(queuedMusic, musicList...)
+	queueLock.Unlock()
+	queueData.Set(musicList)
+}
+
+func takeQueuedMusic() []resource.Music {
+	queueLock.Lock()
+	defer queueLock.Unlock()
+	musicList := queuedMusic
+	queuedMusic = nil
+	return musicList
+}
+
+func containsMusic(musicList []resource.Music, music *resource.Music) bool {
+	return slices.ContainsFunc(musicList, func(m resource.Music) bool { return m.Title == music.Title })
+}
@@ -8,10 +8,12 @@ import (
 	"github.com/hajimehoshi/oto/v2"
 	"meowyplayer.com/source/resource"
 	"meowyplayer.com/source/ui/cwidget"
-	"meowyplayer.com/utility/assert"
 	"meowyplayer.com/utility/container"
 )
 
+// the player stops after the music fails to play this many times in a row
+const maxPlaybackFailures = 3
+
 const (
 	RANDOM = iota
 	ORDERED
@@ -21,30 +23,36 @@ const (
 
 type MusicPlayer struct {
 	resource.PlayList
+	station     *resource.Station //the station being played instead of the play list, nil if none
 	playMode    int
 	history     container.Slice[int]
-	randomQueue container.Slice[int]
+	randomQueue container.Slice[int] //popped from the back
+	queued      container.Slice[int] //the queued music played first in random mode, popped from the front
 
 	//channel to syncrhonize the commands
 	playListChan chan resource.PlayList
+	stationCMD   chan resource.Station
 	progressCMD  chan float64
 	volumeCMD    chan float64
 	playCMD      chan struct{}
 	rollbackCMD  chan struct{}
 	skipCMD      chan struct{}
 	modeCMD      chan int
+	queueCMD     chan struct{}
 }
 
 func NewMusicPlayer() *MusicPlayer {
 	return &MusicPlayer{
 		playMode:     RANDOM,
 		playListChan: make(chan resource.PlayList),
+		stationCMD:   make(chan resource.Station, 16),
 		progressCMD:  make(chan float64, 16),
 		volumeCMD:    make(chan float64, 16),
 		playCMD:      make(chan struct{}, 16),
 		rollbackCMD:  make(chan struct{}, 16),
 		skipCMD:      make(chan struct{}, 16),
 		modeCMD:      make(chan int, 16),
+		queueCMD:     make(chan struct{}, 16),
 	}
 }
 
@@ -72,10 +80,20 @@ func (m *MusicPlayer) CommandMode(mode int) {
 	m.modeCMD <- mode
 }
 
+// the notifications may arrive out of order, so the music is taken from the queue instead
+func (m *MusicPlayer) CommandQueue([]resource.Music) {
+	m.queueCMD <- struct{}{}
+}
+
+func (m *MusicPlayer) CommandStation(station *resource.Station) {
+	m.stationCMD <- *station
+}
+
 func (m *MusicPlayer) setPlayMode(playMode int) {
 	if playMode == RANDOM {
 		m.history.Clear()
 		m.randomQueue = rand.Perm(len(m.Album().MusicList))
+		m.queued.Clear()
 	}
 	m.playMode = playMode
 }
@@ -85,6 +103,16 @@ func (m *MusicPlayer) setPlayList(playList resource.PlayList) {
 	m.setPlayMode(m.playMode)
 }
 
+// queued music is played next in random mode, and after the last one in ordered mode
+func (m *MusicPlayer) queue(musicList []resource.Music) {
+	for _, music := range musicList {
+		m.Album().MusicList.PushBack(music)
+		if m.playMode == RANDOM {
+			m.queued.PushBack(len(m.Album().MusicList) - 1)
+		}
+	}
+}
+
 func (m *MusicPlayer) rollback() {
 	switch m.playMode {
 	case RANDOM:
@@ -105,11 +133,19 @@ func (m *MusicPlayer) rollback() {
 func (m *MusicPlayer) skip() {
 	switch m.playMode {
 	case RANDOM:
+		m.history.PushBack(m.Index())
+
+		//the queued music goes first, in the order it is queued
+		if !m.queued.Empty() {
+			m.SetIndex(m.queued[0])
+			m.queued.Remove(0)
+			return
+		}
+
 		//generate new queue if run out of music
 		if m.randomQueue.Empty() {
 			m.randomQueue = rand.Perm(len(m.Album().MusicList))
 		}
-		m.history.PushBack(m.Index())
 		m.SetIndex(*m.randomQueue.Back())
 		m.randomQueue.PopBack()
 
@@ -121,23 +157,29 @@ func (m *MusicPlayer) skip() {
 	}
 }
 
+// the broken music can't be replayed, so it is skipped in order instead
+func (m *MusicPlayer) skipBroken() {
+	if m.playMode == REPLAY {
+		m.SetIndex((m.Index() + 1) % len(m.Album().MusicList))
+		return
+	}
+	m.skip()
+}
+
 func (m *MusicPlayer) Notify(play *resource.PlayList) {
 	m.playListChan <- *play
 }
 
-func (m *MusicPlayer) Start(menu *cwidget.MediaMenu) {
-	//initialize oto mp3 context
-	context, ready, err := oto.NewContext(resource.SAMPLING_RATE, resource.NUM_OF_CHANNELS, resource.AUDIO_BIT_DEPTH)
-	assert.NoErr(err, "failed to start the music player")
-	<-ready
-
-	//wait for the user to click the music
-WaitLoop:
+// wait for the user to click the music, or to queue some
+func (m *MusicPlayer) waitForMusic() {
 	for {
 		select {
 		case playList := <-m.playListChan:
 			m.setPlayList(playList)
-			break WaitLoop
+			return
+		case station := <-m.stationCMD:
+			m.station = &station
+			return
 		case <-m.skipCMD:
 		case <-m.rollbackCMD:
 		case <-m.playCMD:
@@ -145,61 +187,156 @@ WaitLoop:
 		case <-m.progressCMD:
 		case <-m.volumeCMD:
 			//drain out meaningless commands
+		case <-m.queueCMD:
+			if musicList := takeQueuedMusic(); len(musicList) > 0 {
+				m.playQueue(musicList)
+				return
+			}
 		}
 	}
+}
+
+// nothing is playing, so the queued music is played in order as a play list of its own, named after the shown album
+func (m *MusicPlayer) playQueue(musicList []resource.Music) {
+	album := resource.Album{MusicList: musicList}
+	if shown := albumData.Get(); shown != nil {
+		album.Date, album.Title = shown.Date, shown.Title
+	}
+	playList, err := resource.NewPlayList(&album, &musicList[0])
+	if err != nil {
+		panic(err) //the first music is in the album by construction
+	}
+	m.setPlayList(*playList)
+	for i := 1; i < len(musicList); i++ {
+		m.queued.PushBack(i)
+	}
+}
 
+// open the audio device, then play in the background
+func (m *MusicPlayer) Start(menu *cwidget.MediaMenu) error {
+	context, ready, err := oto.NewContext(resource.SAMPLING_RATE, resource.NUM_OF_CHANNELS, resource.AUDIO_BIT_DEPTH)
+	if err != nil {
+		return fmt.Errorf("failed to open the audio device: %w", err)
+	}
+	go m.run(context, ready, menu)
+	return nil
+}
+
+func (m *MusicPlayer) run(context *oto.Context, ready chan struct{}, menu *cwidget.MediaMenu) {
+	<-ready
+
+	m.waitForMusic()
+	failures := 0
 	for {
+		if m.station != nil {
+			m.playStation(context, menu)
+			continue
+		}
+
 		menu.SetMusic(m.Music())
-		mp3Controller := resource.NewMP3Controller(context, m.PlayList.Music())
+		mp3Controller, err := resource.NewMP3Controller(context, m.PlayList.Music())
+		if err != nil {
+			//the broken music is skipped, unless it is all broken
+			failures++
+			playerLog.Warn("skipped the music", "title", m.Music().Title, "failures", failures, "err", err)
+			if err := setMusicUnplayable(m.Music(), true); err != nil {
+				libraryLog.Error("failed to mark the music unplayable", "title", m.Music().Title, "err", err)
+			}
+			playbackErrorData.Set(err)
+			if failures < maxPlaybackFailures {
+				m.skipBroken()
+			} else {
+				playbackErrorData.Set(fmt.Errorf("stopped after %v music failed to play in a row", failures))
+				m.waitForMusic()
+				failures = 0
+			}
+			continue
+		}
+		failures = 0
+		if err := setMusicUnplayable(m.Music(), false); err != nil {
+			libraryLog.Error("failed to unmark the music unplayable", "title", m.Music().Title, "err", err)
+		}
 		mp3Controller.SetVolume(menu.Volume())
 
+		//the podcast episodes resume where they were left
+		albumTitle, music := m.Album().Title, *m.Music()
+		position, isEpisode := loadEpisodePosition(albumTitle, music.Title)
+		if isEpisode && position > 0 && music.Length > 0 {
+			mp3Controller.SetProgress(min(position.Seconds()/music.Length.Seconds(), 1))
+		}
+		savedAt := time.Now()
+
 		interrupted := false
-		fmt.Println("playing", m.Music().SimpleTitle())
+		playerLog.Info("playing", "title", m.Music().Title, "album", m.Album().Title)
 
 	CONTROL_LOOP:
 		for mp3Controller.PlayOrPause(); !mp3Controller.IsOver(); {
 			select {
 			case playList := <-m.playListChan:
-				fmt.Println("new playlist")
+				playerLog.Debug("new play list")
 				m.setPlayList(playList)
 				interrupted = true
 				break CONTROL_LOOP
 
+			case station := <-m.stationCMD:
+				playerLog.Debug("station", "station", station.Title)
+				m.station = &station
+				interrupted = true
+				break CONTROL_LOOP
+
 			case <-m.skipCMD:
-				fmt.Println("skip")
+				playerLog.Debug("skip")
 				m.skip()
 				interrupted = true
 				break CONTROL_LOOP
 
 			case <-m.rollbackCMD:
-				fmt.Println("rollback")
+				playerLog.Debug("rollback")
 				m.rollback()
 				interrupted = true
 				break CONTROL_LOOP
 
 			case <-m.playCMD:
-				fmt.Println("play/pause")
+				playerLog.Debug("play/pause")
 				mp3Controller.PlayOrPause()
 
 			case playMode := <-m.modeCMD:
-				fmt.Println("mode", playMode)
+				playerLog.Debug("mode", "mode", playMode)
 				m.setPlayMode(playMode)
 
 			case percent := <-m.progressCMD:
-				fmt.Println("progress", percent)
+				playerLog.Debug("progress", "percent", percent)
 				mp3Controller.Pause()
 				time.Sleep(10 * time.Millisecond) //mp3 library has race condition
 				mp3Controller.SetProgress(percent)
 				mp3Controller.Play()
 
+			case <-m.queueCMD:
+				musicList := takeQueuedMusic()
+				playerLog.Debug("queue", "count", len(musicList))
+				m.queue(musicList)
+
 			case volume := <-m.volumeCMD:
-				fmt.Println("set volume")
+				playerLog.Debug("volume", "volume", volume)
 				mp3Controller.SetVolume(volume)
 
 			default:
 				time.Sleep(100 * time.Millisecond)
 			}
 			menu.UpdateProgress(m.PlayList.Music().Length, mp3Controller.CurrentProgressPercent())
+
+			if isEpisode && time.Since(savedAt) > episodeSaveInterval {
+				if err := saveEpisodePosition(albumTitle, music.Title, episodePosition(mp3Controller, &music), false); err != nil {
+					libraryLog.Error("failed to save the episode position", "title", music.Title, "err", err)
+				}
+				savedAt = time.Now()
+			}
+		}
+
+		if isEpisode {
+			if err := saveEpisodePosition(albumTitle, music.Title, episodePosition(mp3Controller, &music), mp3Controller.IsOver()); err != nil {
+				libraryLog.Error("failed to save the episode position", "title", music.Title, "err", err)
+			}
 		}
 
 		if !interrupted {
@@ -209,3 +346,70 @@ WaitLoop:
 		mp3Controller.Close()
 	}
 }
+
+func episodePosition(mp3Controller *resource.MP3Controller, music *resource.Music) time.Duration {
+	return time.Duration(mp3Controller.CurrentProgressPercent() * float64(music.Length))
+}
+
+// play the station until another station or a play list is picked
+// playing a lost station connects to it again
+func (m *MusicPlayer) playStation(context *oto.Context, menu *cwidget.MediaMenu) {
+	station := *m.station
+	menu.SetStation(&station, "connecting...")
+	var lost <-chan struct{}
+	stationController, err := resource.NewStationController(context, &station, func(title string) { menu.SetStation(&station, title) })
+	if err != nil {
+		playerLog.Error("failed to play the station", "station", station.Title, "err", err)
+		menu.SetStation(&station, err.Error())
+	} else {
+		defer stationController.Close()
+		menu.SetStation(&station, stationController.Name())
+		stationController.SetVolume(menu.Volume())
+		stationController.PlayOrPause()
+		lost = stationController.Done()
+	}
+
+	for {
+		select {
+		case playList := <-m.playListChan:
+			playerLog.Debug("new play list")
+			m.station = nil
+			m.setPlayList(playList)
+			return
+
+		case station := <-m.stationCMD:
+			playerLog.Debug("station", "station", station.Title)
+			m.station = &station
+			return
+
+		case <-lost:
+			playerLog.Warn("lost the station", "station", station.Title, "err", stationController.Err())
+			menu.SetStation(&station, "disconnected")
+			lost = nil
+
+		case <-m.playCMD:
+			playerLog.Debug("play/pause")
+			if stationController == nil || stationController.Err() != nil {
+				return
+			}
+			stationController.PlayOrPause()
+
+		case volume := <-m.volumeCMD:
+			playerLog.Debug("volume", "volume", volume)
+			if stationController != nil {
+				stationController.SetVolume(volume)
+			}
+
+		case playMode := <-m.modeCMD:
+			playerLog.Debug("mode", "mode", playMode)
+			m.setPlayMode(playMode)
+
+		case <-m.skipCMD:
+		case <-m.rollbackCMD:
+		case <-m.progressCMD:
+			//a station has neither the order nor the progress
+		case <-m.queueCMD:
+			takeQueuedMusic() //nor a play list to queue into
+		}
+	}
+}
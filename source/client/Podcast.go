@@ -0,0 +1,185 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"meowyplayer.com/source/resource"
+	"meowyplayer.com/utility/json"
+	"meowyplayer.com/utility/network/fileformat"
+	"meowyplayer.com/utility/network/scraper"
+)
+
+const PodcastProvider = "Podcast"
+
+const (
+	podcastCheckInterval = 10 * time.Minute //how often the podcasts are checked for being due
+	episodeSaveInterval  = 15 * time.Second //how often the position of the playing episode is saved
+)
+
+var podcastScraper = scraper.NewPodcastScraper()
+
+// subscribe to the feed as an album titled after the podcast, the episodes are downloaded on demand
+func AddPodcast(ctx context.Context, feedURL string) (resource.Album, error) {
+	podcast, err := podcastScraper.SearchPlayList(ctx, feedURL)
+	if err != nil {
+		return resource.Album{}, err
+	}
+
+	musicLock.Lock()
+	defer musicLock.Unlock()
+	if index := slices.IndexFunc(collectionData.Get().Albums, func(a resource.Album) bool { return a.Feed != nil && a.Feed.URL == podcast.PlayListID }); index != -1 {
+		return resource.Album{}, fmt.Errorf("%w in %q", resource.ErrDuplicateFeed, collectionData.Get().Albums[index].Title)
+	}
+
+	title := playListAlbumTitle(podcast)
+	command := newCommand(fmt.Sprintf("subscribe to %v", title))
+	album, err := addAlbumFromPlayList(command, title, podcast)
+	if err != nil {
+		return resource.Album{}, errors.Join(err, command.rollback())
+	}
+	album.Feed = &resource.Feed{URL: podcast.PlayListID, Refreshed: time.Now(), Episodes: podcast.Videos}
+	return *album, commit(command)
+}
+
+// refresh the podcasts not refreshed within the period, zero refreshes all of them
+func RefreshPodcasts(ctx context.Context, period time.Duration) error {
+	return refreshPodcasts(ctx, func(a *resource.Album) bool { return time.Since(a.Feed.Refreshed) >= period })
+}
+
+func RefreshPodcast(ctx context.Context, album *resource.Album) error {
+	return refreshPodcasts(ctx, func(a *resource.Album) bool { return a.Title == album.Title })
+}
+
+// a podcast that fails to refresh keeps its episodes, the errors are reported together
+func refreshPodcasts(ctx context.Context, due func(*resource.Album) bool) error {
+	musicLock.Lock()
+	feeds := map[string]string{}
+	for i, album := range collectionData.Get().Albums {
+		if album.Feed != nil && due(&collectionData.Get().Albums[i]) {
+			feeds[album.Title] = album.Feed.URL
+		}
+	}
+	musicLock.Unlock()
+
+	//the feeds are fetched without holding the lock
+	errs := []error{}
+	podcasts := map[string]*fileformat.PlayListResult{}
+	for title, feedURL := range feeds {
+		podcast, err := podcastScraper.SearchPlayList(ctx, feedURL)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to refresh %v: %w", title, err))
+			continue
+		}
+		podcasts[title] = podcast
+	}
+	if len(podcasts) == 0 {
+		return errors.Join(errs...)
+	}
+
+	//the episodes are not an undoable change
+	musicLock.Lock()
+	defer musicLock.Unlock()
+	albums := collectionData.Get().Albums
+	for i := range albums {
+		if podcast, ok := podcasts[albums[i].Title]; ok && albums[i].Feed != nil {
+			albums[i].Feed = &resource.Feed{URL: albums[i].Feed.URL, Refreshed: time.Now(), Episodes: podcast.Videos}
+		}
+	}
+	errs = append(errs, reloadCollectionData(), refreshAlbumData())
+	return errors.Join(errs...)
+}
+
+// refresh the podcasts in the background as they fall due
+func StartPodcastRefresh() {
+	go func() {
+		for ; ; time.Sleep(podcastCheckInterval) {
+			if period := configData.Get().PodcastRefresh; period > 0 {
+				if err := RefreshPodcasts(context.Background(), period); err != nil {
+					networkLog.Warn("failed to refresh the podcasts", "err", err)
+				}
+			}
+		}
+	}()
+}
+
+func SetPodcastRefresh(period time.Duration) error {
+	return updateConfig(func(c *resource.Config) { c.PodcastRefresh = period })
+}
+
+// mark the episodes of the current album, the resume positions are dropped
+func SetEpisodesPlayed(musicList []resource.Music, played bool) error {
+	musicLock.Lock()
+	defer musicLock.Unlock()
+	album, err := getSourceAlbum(albumData.Get())
+	if err != nil {
+		return err
+	}
+	state := "unplayed"
+	if played {
+		state = "played"
+	}
+	command := newCommand(fmt.Sprintf("mark %v episodes as %v", len(musicList), state))
+
+	for i := range album.MusicList {
+		if containsMusic(musicList, &album.MusicList[i]) {
+			album.MusicList[i].Played = played
+			album.MusicList[i].Position = 0
+		}
+	}
+	if err := commit(command); err != nil {
+		return err
+	}
+	return reloadAlbumData()
+}
+
+// the music is an episode if its album is subscribed to a podcast
+func findEpisode(albumTitle string, musicTitle string) *resource.Music {
+	albumIndex := slices.IndexFunc(collectionData.Get().Albums, func(a resource.Album) bool { return a.Title == albumTitle })
+	if albumIndex == -1 || collectionData.Get().Albums[albumIndex].Feed == nil {
+		return nil
+	}
+	musicList := collectionData.Get().Albums[albumIndex].MusicList
+	musicIndex := slices.IndexFunc(musicList, func(m resource.Music) bool { return m.Title == musicTitle })
+	if musicIndex == -1 {
+		return nil
+	}
+	return &musicList[musicIndex]
+}
+
+// return where to resume the episode, false if the music is not an episode
+func loadEpisodePosition(albumTitle string, musicTitle string) (time.Duration, bool) {
+	musicLock.Lock()
+	defer musicLock.Unlock()
+	episode := findEpisode(albumTitle, musicTitle)
+	if episode == nil {
+		return 0, false
+	}
+	return episode.Position, true
+}
+
+/*
+Save the position of the episode, the finished one is marked as played and starts over next time.
+The position changes all the time, so it is saved without an undo entry or reloading the views.
+*/
+func saveEpisodePosition(albumTitle string, musicTitle string, position time.Duration, finished bool) error {
+	musicLock.Lock()
+	defer musicLock.Unlock()
+	episode := findEpisode(albumTitle, musicTitle)
+	if episode == nil {
+		return nil
+	}
+
+	if finished {
+		episode.Played, episode.Position = true, 0
+		if err := reloadCollectionData(); err != nil {
+			return err
+		}
+		return refreshAlbumData()
+	}
+	episode.Position = position
+	return json.WriteFile(resource.CollectionPath(), collectionData.Get())
+}
@@ -0,0 +1,130 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"meowyplayer.com/source/resource"
+	"meowyplayer.com/utility/network"
+	"meowyplayer.com/utility/network/downloader"
+	"meowyplayer.com/utility/network/scraper"
+)
+
+// the extraction options of yt-dlp follow the config
+var ytDlpDownloader *downloader.YtDlpDownloader
+
+// a bad setting or a clashing name only costs its own provider, the errors are reported together
+func RegisterProviders() error {
+	errs := []error{}
+	var err error
+	if ytDlpDownloader, err = downloader.NewYtDlpDownloader(configData.Get().YtDlp); err != nil {
+		//the defaults are always valid, the options can then be fixed in the settings
+		errs = append(errs, fmt.Errorf("invalid yt-dlp options, the defaults are used instead: %w", err))
+		ytDlpDownloader, _ = downloader.NewYtDlpDownloader(downloader.DefaultYtDlpOptions())
+	}
+
+	providers := []network.Provider{
+		{
+			Name:            "YouTube",
+			Icon:            resource.YouTubeIcon(),
+			VideoScraper:    scraper.NewClipzagScraper(scraper.ClipzagHost),
+			PlayListScraper: scraper.NewYouTubeFeedScraper(scraper.YouTubeHost),
+			VideoResolver:   scraper.NewYouTubeResolver(scraper.YouTubeHost),
+			MusicDownloader: downloader.NewY2MateDownloader(),
+			Capability:      network.Search | network.PlayList | network.DirectURL,
+		},
+		{
+			Name:            "YouTube (yt-dlp)",
+			Icon:            resource.YouTubeIcon(),
+			VideoScraper:    scraper.NewClipzagScraper(scraper.ClipzagHost),
+			PlayListScraper: scraper.NewYouTubeFeedScraper(scraper.YouTubeHost),
+			VideoResolver:   scraper.NewYouTubeResolver(scraper.YouTubeHost),
+			MusicDownloader: ytDlpDownloader,
+			Capability:      network.Search | network.PlayList | network.DirectURL,
+		},
+		{
+			Name:            "BiliBili",
+			Icon:            resource.BiliBiliIcon(),
+			VideoScraper:    scraper.NewBiliBiliScraper(scraper.BiliBiliHost),
+			MusicDownloader: downloader.NewBiliBiliDownloader(scraper.BiliBiliHost),
+			Capability:      network.Search,
+		},
+		{
+			Name:            PodcastProvider,
+			Icon:            resource.DefaultIcon(),
+			PlayListScraper: podcastScraper,
+			MusicDownloader: downloader.NewEnclosureDownloader(),
+		},
+	}
+	for _, provider := range providers {
+		if err := network.Register(provider); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// return the settings of every registered provider in the configured order, the unlisted ones follow in the registration order
+// the providers without any capability, such as the podcasts, are used directly and have no settings
+func GetProviderSettings() []resource.ProviderSetting {
+	registered := network.Providers()
+	settings := slices.DeleteFunc(slices.Clone(configData.Get().Providers), func(s resource.ProviderSetting) bool {
+		provider, ok := network.Lookup(s.Name)
+		return !ok || provider.Capability == 0
+	})
+	for _, provider := range registered {
+		if provider.Capability != 0 && !slices.ContainsFunc(settings, func(s resource.ProviderSetting) bool { return s.Name == provider.Name }) {
+			settings = append(settings, resource.ProviderSetting{Name: provider.Name, Enabled: true})
+		}
+	}
+	return settings
+}
+
+// return the enabled providers with the capability in the configured order
+func GetProviders(capability network.Capability) []network.Provider {
+	providers := []network.Provider{}
+	for _, setting := range GetProviderSettings() {
+		if provider, ok := network.Lookup(setting.Name); ok && setting.Enabled && provider.Supports(capability) {
+			providers = append(providers, provider)
+		}
+	}
+	return providers
+}
+
+// return the preferred provider if it recognizes the url, otherwise the first enabled one that does, along with the video id
+func GetURLProvider(rawURL string, preferred string) (network.Provider, string, bool) {
+	providers := GetProviders(network.DirectURL)
+	if index := slices.IndexFunc(providers, func(p network.Provider) bool { return p.Name == preferred }); index > 0 {
+		providers = append([]network.Provider{providers[index]}, slices.Delete(providers, index, index+1)...)
+	}
+	for _, provider := range providers {
+		if videoID, ok := provider.VideoResolver.ParseVideoURL(rawURL); ok {
+			return provider, videoID, true
+		}
+	}
+	return network.Provider{}, "", false
+}
+
+func SetProviderEnabled(name string, enabled bool) error {
+	settings := GetProviderSettings()
+	index := slices.IndexFunc(settings, func(s resource.ProviderSetting) bool { return s.Name == name })
+	if index == -1 {
+		return fmt.Errorf("%w: %v", network.ErrProviderNotFound, name)
+	}
+	settings[index].Enabled = enabled
+	return updateConfig(func(c *resource.Config) { c.Providers = settings })
+}
+
+// move the provider up (negative offset) or down (positive offset) in the order
+func MoveProvider(name string, offset int) error {
+	settings := GetProviderSettings()
+	from := slices.IndexFunc(settings, func(s resource.ProviderSetting) bool { return s.Name == name })
+	if from == -1 {
+		return fmt.Errorf("%w: %v", network.ErrProviderNotFound, name)
+	}
+	to := min(max(from+offset, 0), len(settings)-1)
+	setting := settings[from]
+	settings = slices.Insert(slices.Delete(settings, from, from+1), to, setting)
+	return updateConfig(func(c *resource.Config) { c.Providers = settings })
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"os"
+	"slices"
+	"strings"
+
+	"meowyplayer.com/source/resource"
+	"meowyplayer.com/utility/json"
+)
+
+const (
+	searchHistoryLimit = 64
+	suggestionLimit    = 8
+)
+
+// the recent queries, the latest one first
+var searchHistory []string
+
+func LoadSearchHistory() error {
+	searchHistory = []string{}
+	if err := json.ReadFile(resource.SearchHistoryPath(), &searchHistory); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// move the query to the front of the history, the oldest queries are dropped
+func AddSearchHistory(query string) error {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+	history := slices.DeleteFunc(slices.Clone(searchHistory), func(q string) bool { return strings.EqualFold(q, query) })
+	history = append([]string{query}, history...)
+	if len(history) > searchHistoryLimit {
+		history = history[:searchHistoryLimit]
+	}
+	searchHistory = history
+	return json.WriteFile(resource.SearchHistoryPath(), &searchHistory)
+}
+
+// the recent queries containing the text, the text itself is left out
+func SuggestSearch(text string) []string {
+	text = strings.ToLower(strings.TrimSpace(text))
+	suggestions := []string{}
+	if text == "" {
+		return suggestions
+	}
+	for _, query := range searchHistory {
+		if lower := strings.ToLower(query); lower != text && strings.Contains(lower, text) {
+			suggestions = append(suggestions, query)
+		}
+		if len(suggestions) == suggestionLimit {
+			break
+		}
+	}
+	return suggestions
+}
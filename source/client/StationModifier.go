@@ -0,0 +1,68 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"meowyplayer.com/source/resource"
+)
+
+// the title defaults to the host of the url
+func AddStation(title string, rawURL string) error {
+	musicLock.Lock()
+	defer musicLock.Unlock()
+
+	rawURL = strings.TrimSpace(rawURL)
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("not a http stream: %v", rawURL)
+	}
+	if title = strings.TrimSpace(title); title == "" {
+		title = u.Host
+	}
+	if stationExists(title) {
+		return fmt.Errorf("%w: station %q", resource.ErrDuplicateTitle, title)
+	}
+
+	command := newCommand(fmt.Sprintf("add station %v", title))
+	collectionData.Get().Stations.PushBack(resource.Station{Date: time.Now(), Title: title, URL: rawURL})
+	return commit(command)
+}
+
+func UpdateStationTitle(station *resource.Station, title string) error {
+	musicLock.Lock()
+	defer musicLock.Unlock()
+	if stationExists(title) {
+		return fmt.Errorf("%w: station %q", resource.ErrDuplicateTitle, title)
+	}
+
+	stations := collectionData.Get().Stations
+	index := slices.IndexFunc(stations, func(s resource.Station) bool { return s.Title == station.Title })
+	if index == -1 {
+		return fmt.Errorf("%w: %q", resource.ErrStationNotFound, station.Title)
+	}
+	command := newCommand(fmt.Sprintf("rename station %v to %v", station.Title, title))
+	stations[index].Title = title
+	return commit(command)
+}
+
+func DeleteStation(station *resource.Station) error {
+	musicLock.Lock()
+	defer musicLock.Unlock()
+
+	stations := collectionData.Get().Stations
+	index := slices.IndexFunc(stations, func(s resource.Station) bool { return s.Title == station.Title })
+	if index == -1 {
+		return fmt.Errorf("%w: %q", resource.ErrStationNotFound, station.Title)
+	}
+	command := newCommand(fmt.Sprintf("delete station %v", station.Title))
+	collectionData.Get().Stations.Remove(index)
+	return commit(command)
+}
+
+func stationExists(title string) bool {
+	return slices.ContainsFunc(collectionData.Get().Stations, func(s resource.Station) bool { return s.Title == title })
+}
@@ -0,0 +1,14 @@
+package client
+
+import (
+	"meowyplayer.com/source/resource"
+	"meowyplayer.com/utility/network/thumbnail"
+)
+
+const thumbnailWorkers = 4
+
+var thumbnailLoader = thumbnail.NewLoader(resource.ThumbnailCachePath(), thumbnailWorkers)
+
+func GetThumbnailLoader() *thumbnail.Loader {
+	return thumbnailLoader
+}
@@ -0,0 +1,105 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"meowyplayer.com/source/resource"
+	"meowyplayer.com/utility/cleaner"
+	"meowyplayer.com/utility/container"
+)
+
+var titleCleaner atomic.Pointer[cleaner.Cleaner]
+
+// strip the characters that can't be in a file name
+var titleSanitizer = strings.NewReplacer(
+	"<", "",
+	">", "",
+	":", "",
+	"\"", "",
+	"/", "",
+	"\\", "",
+	"|", "",
+	"?", "",
+	"*", "",
+)
+
+func setTitleCleaner(rules *cleaner.Rules) error {
+	c, err := cleaner.New(rules)
+	if err != nil {
+		return err
+	}
+	titleCleaner.Store(c)
+	return nil
+}
+
+// apply the title rules of the config
+func CleanTitle(title string) cleaner.Result {
+	if c := titleCleaner.Load(); c != nil {
+		return c.Clean(title)
+	}
+	return cleaner.Result{Title: title}
+}
+
+// the music file name the title would be cleaned into, the extension is kept
+func CleanMusicTitle(music *resource.Music) (resource.Music, cleaner.Result) {
+	result := CleanTitle(music.SimpleTitle())
+	cleaned := *music
+	cleaned.Title = titleSanitizer.Replace(result.Title) + filepath.Ext(music.Title)
+	if result.Artist != "" {
+		cleaned.Artist = result.Artist
+	}
+	return cleaned, result
+}
+
+// rename the music files by the title rules, every album (and trash) sharing a file follows it
+// the music whose cleaned title is taken by another file is skipped
+func CleanMusicTitles(musicList []resource.Music) error {
+	musicLock.Lock()
+	defer musicLock.Unlock()
+	command := newCommand(fmt.Sprintf("clean %v titles", len(musicList)))
+
+	for _, music := range musicList {
+		cleaned, _ := CleanMusicTitle(&music)
+		if cleaned.Title == music.Title && cleaned.Artist == music.Artist {
+			continue
+		}
+		if cleaned.Title != music.Title {
+			//the case only renames are the same file on some file systems
+			if _, err := os.Stat(resource.MusicPath(&cleaned)); err == nil && !strings.EqualFold(cleaned.Title, music.Title) {
+				libraryLog.Warn("skipped cleaning the title, the cleaned one already exists", "title", music.Title, "cleaned", cleaned.Title)
+				continue
+			}
+			if err := command.moveFile(resource.MusicPath(&music), resource.MusicPath(&cleaned)); err != nil {
+				return errors.Join(err, command.rollback())
+			}
+		}
+		renameMusic(&music, &cleaned)
+	}
+
+	if err := commit(command); err != nil {
+		return err
+	}
+	return reloadAlbumData()
+}
+
+func renameMusic(music *resource.Music, renamed *resource.Music) {
+	rename := func(musicList container.Slice[resource.Music]) {
+		for i := range musicList {
+			if musicList[i].Title == music.Title {
+				musicList[i].Title = renamed.Title
+				musicList[i].Artist = renamed.Artist
+			}
+		}
+	}
+	for i := range collectionData.Get().Albums {
+		rename(collectionData.Get().Albums[i].MusicList)
+	}
+	for i := range collectionData.Get().Trash {
+		rename(collectionData.Get().Trash[i].Album.MusicList)
+	}
+}
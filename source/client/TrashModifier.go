@@ -0,0 +1,129 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"meowyplayer.com/source/resource"
+)
+
+func RestoreTrash(trash *resource.Trash) error {
+	musicLock.Lock()
+	defer musicLock.Unlock()
+	collection := collectionData.Get()
+	index := indexOfTrash(trash)
+	if index == -1 {
+		return fmt.Errorf("%w: %q", resource.ErrTrashNotFound, trash.Album.Title)
+	}
+	source := collection.Trash[index]
+	command := newCommand(fmt.Sprintf("restore %v", source.Album.Title))
+
+	if source.IsAlbum {
+		//restore as a new album, in case another album has taken the title
+		album := source.Album
+		album.MusicList = slices.Clone(source.Album.MusicList)
+		if albumExists(album.Title) {
+			album.Title = generateAlbumTitle(album.Title)
+		}
+		if source.Cover != "" {
+			if err := command.moveFile(source.Cover, resource.CoverPath(&album)); err != nil {
+				return errors.Join(err, command.rollback())
+			}
+		}
+		collection.Albums.PushBack(album)
+	} else {
+		//put the music back to its album, recreate the album if it is gone
+		if !albumExists(source.Album.Title) {
+			if _, err := addAlbum(command, source.Album.Title); err != nil {
+				return errors.Join(err, command.rollback())
+			}
+		}
+		album, err := getSourceAlbum(&source.Album)
+		if err != nil {
+			return errors.Join(err, command.rollback())
+		}
+		for _, music := range source.Album.MusicList {
+			if !containsMusic(album.MusicList, &music) {
+				album.MusicList.PushBack(music)
+			}
+		}
+	}
+
+	collection.Trash.Remove(index)
+	if err := commit(command); err != nil {
+		return err
+	}
+	return reloadAlbumData()
+}
+
+// permanently remove the trash, along with the history that refers to it
+func PurgeTrash(trash *resource.Trash) error {
+	musicLock.Lock()
+	defer musicLock.Unlock()
+	collection := collectionData.Get()
+	index := indexOfTrash(trash)
+	if index == -1 {
+		return fmt.Errorf("%w: %q", resource.ErrTrashNotFound, trash.Album.Title)
+	}
+
+	source := collection.Trash[index]
+	collection.Trash.Remove(index)
+	purgeTrashFiles(&source)
+	if err := forgetTrash([]resource.Trash{source}); err != nil {
+		return err
+	}
+	return reloadCollectionData()
+}
+
+// purge the trash that has been kept longer than the retention period
+func PurgeExpiredTrash() error {
+	musicLock.Lock()
+	defer musicLock.Unlock()
+	retention := configData.Get().TrashRetention
+	if retention <= 0 {
+		return nil
+	}
+
+	collection := collectionData.Get()
+	isExpired := func(t resource.Trash) bool { return time.Since(t.Date) > retention }
+	expired := collection.Trash.Filter(isExpired)
+	if expired.Empty() {
+		return nil
+	}
+
+	collection.Trash = collection.Trash.Filter(func(t resource.Trash) bool { return !isExpired(t) })
+	for i := range expired {
+		purgeTrashFiles(&expired[i])
+	}
+	if err := forgetTrash(expired); err != nil {
+		return err
+	}
+	return reloadCollectionData()
+}
+
+// remove the cover, and the music files no longer used by any album or trash
+func purgeTrashFiles(trash *resource.Trash) {
+	if trash.Cover != "" {
+		os.Remove(trash.Cover)
+	}
+	for _, music := range trash.Album.MusicList {
+		if !musicInUse(&music) {
+			os.Remove(resource.MusicPath(&music))
+		}
+	}
+}
+
+func musicInUse(music *resource.Music) bool {
+	collection := collectionData.Get()
+	inAlbum := slices.ContainsFunc(collection.Albums, func(a resource.Album) bool { return containsMusic(a.MusicList, music) })
+	inTrash := slices.ContainsFunc(collection.Trash, func(t resource.Trash) bool { return containsMusic(t.Album.MusicList, music) })
+	return inAlbum || inTrash
+}
+
+// the deletion time is unique enough to identify the trash
+func indexOfTrash(trash *resource.Trash) int {
+	return slices.IndexFunc(collectionData.Get().Trash, func(t resource.Trash) bool { return t.Date.Equal(trash.Date) })
+}
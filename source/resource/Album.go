@@ -6,6 +6,7 @@ import (
 
 	"fyne.io/fyne/v2"
 	"meowyplayer.com/utility/container"
+	"meowyplayer.com/utility/network/fileformat"
 )
 
 type Album struct {
@@ -13,8 +14,19 @@ type Album struct {
 	Title     string                 `json:"title"`
 	MusicList container.Slice[Music] `json:"musicList"`
 	Cover     fyne.Resource          `json:"-"`
+	Feed      *Feed                  `json:"feed,omitempty"` //nil for the albums not subscribed to a podcast
+}
+
+// the podcast an album is subscribed to, the episodes are replaced on every refresh
+type Feed struct {
+	URL       string                   `json:"url"`
+	Refreshed time.Time                `json:"refreshed"`
+	Episodes  []fileformat.VideoResult `json:"episodes"` //newest first, as listed by the feed
 }
 
 func (a *Album) Description() string {
+	if a.Feed != nil {
+		return fmt.Sprintf("%v\n\nEpisodes: %v/%v\n\nRefreshed: %v", a.Title, len(a.MusicList), len(a.Feed.Episodes), a.Feed.Refreshed.Format(time.DateTime))
+	}
 	return fmt.Sprintf("%v\n\nMusic: %v\n\n%v", a.Title, len(a.MusicList), a.Date.Format(time.DateTime))
 }
@@ -9,4 +9,7 @@ import (
 type Collection struct {
 	Date   time.Time              `json:"date"`
 	Albums container.Slice[Album] `json:"albums"`
+	Trash  container.Slice[Trash] `json:"trash"`
+
+	Stations container.Slice[Station] `json:"stations"`
 }
@@ -0,0 +1,28 @@
+package resource
+
+import (
+	"time"
+
+	"meowyplayer.com/utility/cleaner"
+	"meowyplayer.com/utility/network/downloader"
+	"meowyplayer.com/utility/network/httpclient"
+)
+
+type ProviderSetting struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+type Config struct {
+	TrashRetention time.Duration           `json:"trashRetention"` //zero keeps the trash forever
+	Providers      []ProviderSetting       `json:"providers"`      //in the displayed order, unlisted providers are enabled
+	Network        httpclient.Config       `json:"network"`
+	Downloads      int                     `json:"downloads"`  //the number of downloads running in parallel
+	TitleRules     cleaner.Rules           `json:"titleRules"` //applied to the titles of the downloaded music
+	YtDlp          downloader.YtDlpOptions `json:"ytDlp"`
+	PodcastRefresh time.Duration           `json:"podcastRefresh"` //zero only refreshes the podcasts on demand
+}
+
+func DefaultConfig() Config {
+	return Config{TrashRetention: 30 * 24 * time.Hour, Network: httpclient.DefaultConfig(), Downloads: 3, TitleRules: cleaner.DefaultRules(), YtDlp: downloader.DefaultYtDlpOptions(), PodcastRefresh: time.Hour}
+}
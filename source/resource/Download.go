@@ -0,0 +1,35 @@
+package resource
+
+import (
+	"time"
+
+	"meowyplayer.com/utility/network/fileformat"
+)
+
+type DownloadState string
+
+const (
+	DownloadQueued    DownloadState = "queued"
+	DownloadRunning   DownloadState = "running"
+	DownloadCompleted DownloadState = "completed"
+	DownloadFailed    DownloadState = "failed"
+	DownloadCancelled DownloadState = "cancelled"
+)
+
+type DownloadJob struct {
+	ID         string                 `json:"id"`
+	Date       time.Time              `json:"date"`
+	Provider   string                 `json:"provider"`
+	Video      fileformat.VideoResult `json:"video"`
+	AlbumTitle string                 `json:"albumTitle"`
+	State      DownloadState          `json:"state"`
+	Error      string                 `json:"error"`
+	Attempts   int                    `json:"attempts"`
+	Received   int64                  `json:"received"`
+	Total      int64                  `json:"total"` //-1 if unknown
+	Speed      float64                `json:"-"`     //bytes per second
+}
+
+func (d *DownloadJob) Finished() bool {
+	return d.State == DownloadCompleted || d.State == DownloadFailed || d.State == DownloadCancelled
+}
@@ -0,0 +1,17 @@
+package resource
+
+import "errors"
+
+// the library entries looked up by their titles, wrapped with the title that is not found or taken
+var (
+	ErrAlbumNotFound    = errors.New("album not found")
+	ErrTrackNotFound    = errors.New("music not found")
+	ErrStationNotFound  = errors.New("station not found")
+	ErrTrashNotFound    = errors.New("no longer in the trash")
+	ErrDownloadNotFound = errors.New("download not in the queue")
+	ErrDuplicateTitle   = errors.New("title already taken")
+	ErrDuplicateFeed    = errors.New("podcast already subscribed")
+)
+
+// the settings in the config that can't be applied, they are replaced with the defaults
+var ErrInvalidConfig = errors.New("invalid settings, the defaults are used instead")
@@ -1,10 +1,8 @@
 package resource
 
 import (
-	"os"
-
 	"fyne.io/fyne/v2"
-	"meowyplayer.com/utility/assert"
+	"fyne.io/fyne/v2/theme"
 )
 
 const (
@@ -22,10 +20,13 @@ const (
 
 func getResource(resourcePath string) fyne.Resource {
 	asset, err := fyne.LoadResourceFromPath(resourcePath)
-	if os.IsNotExist(err) {
+	if err != nil {
 		asset, err = fyne.LoadResourceFromPath(AssetPath(iconNameMissing))
 	}
-	assert.NoErr(err, "failed to load icon resource")
+	if err != nil {
+		//the assets folder itself is missing, the icon built into fyne stands in
+		return theme.BrokenImageIcon()
+	}
 	return asset
 }
 
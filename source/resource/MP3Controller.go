@@ -1,28 +1,28 @@
 package resource
 
 import (
-	"bytes"
+	"fmt"
 	"io"
-	"os"
 	"sync"
 
-	"github.com/hajimehoshi/go-mp3"
 	"github.com/hajimehoshi/oto/v2"
-	"meowyplayer.com/utility/assert"
+	"meowyplayer.com/utility/audio"
 )
 
+// the music is decoded from the file as it plays
 type MP3Controller struct {
 	mutex sync.Mutex
-	*mp3.Decoder
+	*audio.MP3File
 	oto.Player
 }
 
-func NewMP3Controller(context *oto.Context, music *Music) *MP3Controller {
-	mp3Data, err := os.ReadFile(MusicPath(music))
-	assert.NoErr(err, "failed to load mp3 file")
-	mp3Decoder, err := mp3.NewDecoder(bytes.NewReader(mp3Data))
-	assert.NoErr(err, "failed to decode mp3 data")
-	return &MP3Controller{Decoder: mp3Decoder, Player: context.NewPlayer(mp3Decoder)}
+// fail if the file is missing or cannot be decoded
+func NewMP3Controller(context *oto.Context, music *Music) (*MP3Controller, error) {
+	mp3File, err := audio.OpenMP3(MusicPath(music))
+	if err != nil {
+		return nil, fmt.Errorf("failed to play %v: %w", music.SimpleTitle(), err)
+	}
+	return &MP3Controller{MP3File: mp3File, Player: context.NewPlayer(mp3File)}, nil
 }
 
 func (m *MP3Controller) CurrentProgressBytes() int64 {
@@ -53,3 +53,10 @@ func (m *MP3Controller) PlayOrPause() {
 		m.Play()
 	}
 }
+
+// the player stops reading before the file is closed
+func (m *MP3Controller) Close() error {
+	err := m.Player.Close()
+	m.MP3File.Close()
+	return err
+}
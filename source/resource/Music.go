@@ -15,6 +15,26 @@ type Music struct {
 	Date   time.Time     `json:"date"`
 	Title  string        `json:"title"`
 	Length time.Duration `json:"length"`
+	Artist string        `json:"artist,omitempty"`
+	Source *Source       `json:"source,omitempty"` //nil for the local files
+
+	Unplayable bool `json:"unplayable,omitempty"` //the file failed to play the last time
+
+	//kept for the podcast episodes only
+	Played   bool          `json:"played,omitempty"`
+	Position time.Duration `json:"position,omitempty"` //where to resume, zero if not started
+}
+
+// where the downloaded music came from, enough to download it again
+type Source struct {
+	Provider     string    `json:"provider"`
+	VideoID      string    `json:"videoID"`
+	URL          string    `json:"url"`
+	AudioURL     string    `json:"audioURL,omitempty"` //the podcast enclosure
+	ChannelID    string    `json:"channelID"`
+	ChannelTitle string    `json:"channelTitle"`
+	Description  string    `json:"description"`
+	Date         time.Time `json:"date"` //when it was downloaded
 }
 
 // return title without the extension string
@@ -27,5 +47,14 @@ func (m *Music) Description() string {
 	const kConversionFactor = 60
 	mins := int(m.Length.Minutes()) % kConversionFactor
 	secs := int(m.Length.Seconds()) % kConversionFactor
-	return fmt.Sprintf("%02v:%02v | %v", mins, secs, m.SimpleTitle())
+	state := ""
+	switch {
+	case m.Unplayable:
+		state = "✗ "
+	case m.Played:
+		state = "✓ "
+	case m.Position > 0:
+		state = "◐ "
+	}
+	return fmt.Sprintf("%v%02v:%02v | %v", state, mins, secs, m.SimpleTitle())
 }
@@ -5,23 +5,59 @@ import (
 	"path/filepath"
 	"time"
 
-	"meowyplayer.com/utility/assert"
 	"meowyplayer.com/utility/json"
 )
 
 const (
 	albumPath      = "album"
 	coverPath      = "cover"
+	trashPath      = "trash"
 	collectionFile = "collection.json"
+	historyFile    = "history.json"
+	downloadPath   = "download"
+	downloadFile   = "download.json"
+	cachePath      = "cache"
+	thumbnailPath  = "thumbnail"
 
-	musicPath = "music"
-	assetPath = "asset"
+	musicPath  = "music"
+	assetPath  = "asset"
+	configFile = "config.json"
+	searchFile = "search.json"
 )
 
 func CollectionPath() string {
 	return filepath.Join(albumPath, collectionFile)
 }
 
+func ConfigPath() string {
+	return configFile
+}
+
+func SearchHistoryPath() string {
+	return searchFile
+}
+
+func HistoryPath() string {
+	return filepath.Join(albumPath, historyFile)
+}
+
+func TrashPath() string {
+	return filepath.Join(albumPath, trashPath)
+}
+
+func DownloadQueuePath() string {
+	return filepath.Join(downloadPath, downloadFile)
+}
+
+// the partial file of the download job, kept until the job completes or is removed
+func DownloadPartPath(job *DownloadJob) string {
+	return filepath.Join(downloadPath, job.ID+".part")
+}
+
+func ThumbnailCachePath() string {
+	return filepath.Join(cachePath, thumbnailPath)
+}
+
 func CoverPath(album *Album) string {
 	return filepath.Join(albumPath, coverPath, album.Title+".png")
 }
@@ -34,15 +70,17 @@ func AssetPath(assetName string) string {
 	return filepath.Join(assetPath, assetName)
 }
 
-func MakeNecessaryPath() {
-	assert.NoErr(os.MkdirAll(filepath.Join(albumPath, coverPath), os.ModePerm), "failed to create cover directory")
-	assert.NoErr(os.MkdirAll(filepath.Join(musicPath), os.ModePerm), "failed to create music directory")
+func MakeNecessaryPath() error {
+	for _, path := range []string{filepath.Join(albumPath, coverPath), musicPath, TrashPath(), downloadPath, ThumbnailCachePath()} {
+		if err := os.MkdirAll(path, os.ModePerm); err != nil {
+			return err
+		}
+	}
 
 	_, err := os.Stat(CollectionPath())
 	if os.IsNotExist(err) {
 		//create default collection
-		assert.NoErr(json.WriteFile(CollectionPath(), &Collection{Date: time.Now(), Albums: nil}), "failed to create default collection")
-	} else {
-		assert.NoErr(err, "failed to fetch collection path stats")
+		return json.WriteFile(CollectionPath(), &Collection{Date: time.Now(), Albums: nil})
 	}
+	return err
 }
@@ -1,8 +1,8 @@
 package resource
 
 import (
-	"golang.org/x/exp/slices"
-	"meowyplayer.com/utility/assert"
+	"fmt"
+	"slices"
 )
 
 type PlayList struct {
@@ -10,10 +10,12 @@ type PlayList struct {
 	index int
 }
 
-func NewPlayList(album *Album, music *Music) *PlayList {
-	index := slices.Index(album.MusicList, *music)
-	assert.Ensure(func() bool { return index != -1 })
-	return &PlayList{*album, index}
+func NewPlayList(album *Album, music *Music) (*PlayList, error) {
+	index := slices.IndexFunc(album.MusicList, func(m Music) bool { return m.Title == music.Title })
+	if index == -1 {
+		return nil, fmt.Errorf("%w: %q in %q", ErrTrackNotFound, music.Title, album.Title)
+	}
+	return &PlayList{*album, index}, nil
 }
 
 func (p *PlayList) Album() *Album {
@@ -28,7 +30,10 @@ func (p *PlayList) Index() int {
 	return p.index
 }
 
+// the index is computed by the player from the album, out of range is a bug
 func (p *PlayList) SetIndex(musicIndex int) {
-	assert.Ensure(func() bool { return 0 <= musicIndex && musicIndex < len(p.album.MusicList) })
+	if musicIndex < 0 || len(p.album.MusicList) <= musicIndex {
+		panic(fmt.Sprintf("music index %v out of range [0, %v)", musicIndex, len(p.album.MusicList)))
+	}
 	p.index = musicIndex
 }
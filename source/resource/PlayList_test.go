@@ -0,0 +1,40 @@
+package resource_test
+
+import (
+	"errors"
+	"testing"
+
+	"meowyplayer.com/source/resource"
+)
+
+func TestNewPlayList(t *testing.T) {
+	album := resource.Album{Title: "Album", MusicList: []resource.Music{{Title: "a.mp3"}, {Title: "b.mp3"}}}
+	playList, err := resource.NewPlayList(&album, &resource.Music{Title: "b.mp3"})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if playList.Index() != 1 || playList.Music().Title != "b.mp3" {
+		t.Errorf("unexpected music: %v %v\n", playList.Index(), playList.Music().Title)
+	}
+}
+
+func TestNewPlayListTrackNotFound(t *testing.T) {
+	album := resource.Album{Title: "Album", MusicList: []resource.Music{{Title: "a.mp3"}}}
+	if _, err := resource.NewPlayList(&album, &resource.Music{Title: "missing.mp3"}); !errors.Is(err, resource.ErrTrackNotFound) {
+		t.Errorf("expected %v, got %v\n", resource.ErrTrackNotFound, err)
+	}
+}
+
+func TestSetIndexOutOfRange(t *testing.T) {
+	album := resource.Album{Title: "Album", MusicList: []resource.Music{{Title: "a.mp3"}}}
+	playList, err := resource.NewPlayList(&album, &album.MusicList[0])
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected the index out of range to panic\n")
+		}
+	}()
+	playList.SetIndex(1)
+}
@@ -0,0 +1,17 @@
+package resource
+
+import (
+	"fmt"
+	"time"
+)
+
+// an internet radio streaming mp3, such as an Icecast or SHOUTcast station
+type Station struct {
+	Date  time.Time `json:"date"`
+	Title string    `json:"title"`
+	URL   string    `json:"url"`
+}
+
+func (s *Station) Description() string {
+	return fmt.Sprintf("%v\n%v", s.Title, s.URL)
+}
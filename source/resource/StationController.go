@@ -0,0 +1,61 @@
+package resource
+
+import (
+	"context"
+
+	"github.com/hajimehoshi/go-mp3"
+	"github.com/hajimehoshi/oto/v2"
+	"meowyplayer.com/utility/network/radio"
+)
+
+// play the station as it streams, a station can't be seeked
+type StationController struct {
+	stream *radio.Stream
+	player oto.Player
+}
+
+// the titles announced by the station are passed to onTitle
+func NewStationController(otoContext *oto.Context, station *Station, onTitle func(string)) (*StationController, error) {
+	stream, err := radio.Open(context.Background(), station.URL, radio.DefaultOptions(), onTitle)
+	if err != nil {
+		return nil, err
+	}
+	decoder, err := mp3.NewDecoder(stream)
+	if err != nil {
+		stream.Close()
+		return nil, err
+	}
+	return &StationController{stream: stream, player: otoContext.NewPlayer(decoder)}, nil
+}
+
+// the name told by the station, empty if unknown
+func (s *StationController) Name() string {
+	return s.stream.Name
+}
+
+func (s *StationController) PlayOrPause() {
+	if s.player.IsPlaying() {
+		s.player.Pause()
+	} else {
+		s.player.Play()
+	}
+}
+
+func (s *StationController) SetVolume(volume float64) {
+	s.player.SetVolume(volume)
+}
+
+// closed once the station is lost for good
+func (s *StationController) Done() <-chan struct{} {
+	return s.stream.Done()
+}
+
+func (s *StationController) Err() error {
+	return s.stream.Err()
+}
+
+// the stream is closed first, so that the player is not left waiting for the audio
+func (s *StationController) Close() error {
+	s.stream.Close()
+	return s.player.Close()
+}
@@ -0,0 +1,22 @@
+package resource
+
+import (
+	"fmt"
+	"time"
+)
+
+// a deleted album, or the music deleted from an album
+type Trash struct {
+	Date    time.Time `json:"date"`
+	IsAlbum bool      `json:"isAlbum"`
+	Album   Album     `json:"album"`
+	Cover   string    `json:"cover"`
+}
+
+func (t *Trash) Description() string {
+	kind := "Music from"
+	if t.IsAlbum {
+		kind = "Album"
+	}
+	return fmt.Sprintf("%v %v | Music: %v | %v", kind, t.Album.Title, len(t.Album.MusicList), t.Date.Format(time.DateTime))
+}
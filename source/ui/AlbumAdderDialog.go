@@ -1,15 +1,164 @@
 package ui
 
 import (
+	"context"
 	"fmt"
+	"sync"
+	"sync/atomic"
 
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
 	"meowyplayer.com/source/client"
+	"meowyplayer.com/source/ui/cwidget"
+	"meowyplayer.com/utility/network"
+	"meowyplayer.com/utility/network/fileformat"
+	"meowyplayer.com/utility/network/scraper"
+	"meowyplayer.com/utility/pattern"
 )
 
 func showAddLocalAlbumDialog() {
 	showErrorIfAny(client.AddAlbum())
 }
 
+// the play list found by a search, along with the provider it is downloaded from
+type playListSession struct {
+	provider network.Provider
+	playList *fileformat.PlayListResult
+}
+
 func showAddOnlineAlbumDialog() {
-	fmt.Println("not completed")
+	providers := client.GetProviders(network.Search | network.PlayList)
+	if len(providers) == 0 {
+		showErrorIfAny(fmt.Errorf("no play list provider is enabled"))
+		return
+	}
+	var provider network.Provider
+	platformMenu := newProviderDropDown(providers, func(selected network.Provider) { provider = selected })
+
+	//the entries to pick from, all of them are picked by default
+	//the unpicked ones are only touched on the ui goroutine, while the play list is found in the background
+	session := atomic.Pointer[playListSession]{}
+	unpicked := map[string]bool{}
+	playListData := pattern.Data[[]fileformat.VideoResult]{}
+	playListViewList := cwidget.NewViewList[fileformat.VideoResult](&playListData, container.NewVBox(),
+		func(result fileformat.VideoResult) fyne.CanvasObject {
+			check := widget.NewCheck(result.Title, func(checked bool) { unpicked[result.VideoID] = !checked })
+			check.SetChecked(!unpicked[result.VideoID])
+			return check
+		},
+	)
+
+	titleEntry := widget.NewEntry()
+	titleEntry.SetPlaceHolder("Album Title")
+	status := widget.NewLabel("")
+
+	//search bar, a new search cancels the one in flight
+	cancelSearch := context.CancelFunc(func() {})
+	searchBar := widget.NewEntry()
+	searchBar.SetPlaceHolder("Play List / Channel URL or Search Video")
+	searchBar.ActionItem = cwidget.NewButtonWithIcon("", theme.SearchIcon(), func() { searchBar.OnSubmitted(searchBar.Text) })
+	searchBar.OnSubmitted = func(query string) {
+		cancelSearch()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancelSearch = cancel
+		unpicked = map[string]bool{}
+		status.SetText("searching...")
+		selected := provider
+		go func() {
+			defer cancel()
+			result, err := searchPlayList(ctx, &selected, query)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				status.SetText("")
+				showSearchError(&selected, err)
+				return
+			}
+
+			session.Store(&playListSession{provider: selected, playList: result})
+			titleEntry.SetText(result.Title)
+			if result.Truncated {
+				status.SetText(fmt.Sprintf("%v entries, YouTube only lists the latest ones, the older entries are left out", len(result.Videos)))
+			} else {
+				status.SetText(fmt.Sprintf("%v entries", len(result.Videos)))
+			}
+			playListData.Set(result.Videos)
+		}()
+	}
+
+	var onlineAlbumDialog dialog.Dialog
+	create := cwidget.NewButtonWithIcon("Create", theme.ConfirmIcon(), func() {
+		found := session.Load()
+		if found == nil {
+			return
+		}
+		created := *found.playList
+		created.Title = titleEntry.Text
+		videos := []fileformat.VideoResult{}
+		for _, video := range created.Videos {
+			if !unpicked[video.VideoID] {
+				videos = append(videos, video)
+			}
+		}
+		onlineAlbumDialog.Hide()
+		go downloadPlayList(context.Background(), &created, videos, found.provider.Name)
+	})
+
+	onlineAlbumDialog = dialog.NewCustom("", "O", container.NewBorder(
+		container.NewVBox(container.NewBorder(nil, nil, platformMenu, nil, searchBar), container.NewBorder(nil, nil, nil, create, titleEntry), status),
+		nil,
+		nil,
+		nil,
+		playListViewList,
+	), getWindow())
+	onlineAlbumDialog.SetOnClosed(func() { cancelSearch() })
+	onlineAlbumDialog.Resize(getWindow().Canvas().Size())
+	onlineAlbumDialog.Show()
+}
+
+// urls are scraped as play lists, anything else is searched and listed as a play list titled after the query
+func searchPlayList(ctx context.Context, provider *network.Provider, query string) (*fileformat.PlayListResult, error) {
+	if _, ok := scraper.ParsePlayListURL(query); ok {
+		return provider.PlayListScraper.SearchPlayList(ctx, query)
+	}
+
+	videos, err := provider.VideoScraper.Search(ctx, query, scraper.SearchOptions{Page: 1})
+	if err != nil {
+		return nil, err
+	}
+	playList := &fileformat.PlayListResult{Title: query, Videos: videos}
+	if len(videos) > 0 {
+		playList.ThumbnailURL = videos[0].ThumbnailURL
+	}
+	return playList, nil
+}
+
+// create the album, then queue the entries to be downloaded into it
+func downloadPlayList(ctx context.Context, playList *fileformat.PlayListResult, videos []fileformat.VideoResult, provider string) {
+	album, err := client.AddAlbumFromPlayList(playList)
+	if err != nil {
+		showErrorIfAny(err)
+		return
+	}
+
+	failed := atomic.Int64{}
+	wg := sync.WaitGroup{}
+	wg.Add(len(videos))
+	for i := range videos {
+		go func(video *fileformat.VideoResult) {
+			defer wg.Done()
+			if err := client.Download(ctx, provider, video, &album); err != nil {
+				failed.Add(1)
+			}
+		}(&videos[i])
+	}
+	wg.Wait()
+
+	if failed.Load() > 0 {
+		showErrorIfAny(fmt.Errorf("failed to download %v of %v entries into %v, see the downloads tab", failed.Load(), len(videos), album.Title))
+	}
 }
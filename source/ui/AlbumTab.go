@@ -2,7 +2,6 @@ package ui
 
 import (
 	"fmt"
-	"log"
 	"strings"
 
 	"fyne.io/fyne/v2"
@@ -23,20 +22,35 @@ func newAlbumTab() *container.TabItem {
 
 	albumAdderLocal := cwidget.NewButtonWithIcon("", theme.ContentAddIcon(), showAddLocalAlbumDialog)
 	albumAdderOnline := cwidget.NewButtonWithIcon("", resource.AlbumAdderOnlineIcon(), showAddOnlineAlbumDialog)
+	podcastAdder := cwidget.NewButtonWithIcon("", theme.VolumeUpIcon(), showAddPodcastDialog)
+	viewList := newAlbumViewList(&data)
 
-	return container.NewTabItemWithIcon("Album", resource.AlbumTabIcon(), container.NewBorder(
+	tab := container.NewTabItemWithIcon("Album", resource.AlbumTabIcon(), container.NewBorder(
 		container.NewBorder(
 			nil,
 			container.NewGridWithRows(1, newAlbumTitleButton(&data, "Title"), newAlbumDateButton(&data, "Date")),
 			nil,
-			container.NewGridWithRows(1, albumAdderLocal, albumAdderOnline),
+			container.NewGridWithRows(1, albumAdderLocal, albumAdderOnline, podcastAdder),
 			newAlbumSearchBar(&data),
 		),
 		nil,
 		nil,
 		nil,
-		newAlbumViewList(&data),
+		viewList,
 	))
+	dropHandlers[tab] = func(pos fyne.Position, uris []fyne.URI) { dropOnAlbumTab(viewList, pos, getLocalPaths(uris)) }
+	return tab
+}
+
+// files dropped onto an album are added to it, folders dropped elsewhere become new albums
+func dropOnAlbumTab(viewList *cwidget.ViewList[resource.Album], pos fyne.Position, paths []string) {
+	if album, ok := viewList.ItemAt(pos); ok {
+		libraryLog.Info("drop onto the album", "count", len(paths), "album", album.Title)
+		showErrorIfAny(client.AddMusicFromPaths(&album, paths))
+	} else {
+		libraryLog.Info("drop onto the album tab", "count", len(paths))
+		showErrorIfAny(client.AddAlbumsFromFolders(paths))
+	}
 }
 
 func newAlbumViewList(data *cbinding.AlbumDataList) *cwidget.ViewList[resource.Album] {
@@ -90,7 +104,14 @@ func showAlbumMenu(album *resource.Album, canvas fyne.Canvas, pos fyne.Position)
 	rename := fyne.NewMenuItem("Rename", makeRenameDialog(album))
 	cover := fyne.NewMenuItem("Cover", makeCoverDialog(album))
 	delete := fyne.NewMenuItem("Delete", makeDeleteAlbumDialog(album))
-	widget.ShowPopUpMenuAtPosition(fyne.NewMenu("", rename, cover, delete), canvas, pos)
+	menu := fyne.NewMenu("", rename, cover, delete)
+
+	//the podcast albums list their episodes
+	if album.Feed != nil {
+		episodes := fyne.NewMenuItem("Episodes", func() { showEpisodesDialog(album) })
+		menu.Items = append(menu.Items, fyne.NewMenuItemSeparator(), episodes)
+	}
+	widget.ShowPopUpMenuAtPosition(menu, canvas, pos)
 }
 
 func makeRenameDialog(album *resource.Album) func() {
@@ -98,7 +119,7 @@ func makeRenameDialog(album *resource.Album) func() {
 	return func() {
 		dialog.ShowCustomConfirm("Enter title:", "Confirm", "Cancel", entry, func(rename bool) {
 			if rename {
-				log.Printf("rename %v to %v\n", album.Title, entry.Text)
+				libraryLog.Info("rename the album", "album", album.Title, "title", entry.Text)
 				showErrorIfAny(client.UpdateAlbumTitle(album, entry.Text))
 			}
 		}, getWindow())
@@ -111,7 +132,7 @@ func makeCoverDialog(album *resource.Album) func() {
 			if err != nil {
 				showErrorIfAny(err)
 			} else if result != nil {
-				log.Printf("update %v's cover: %v\n", album.Title, result.URI().Path())
+				libraryLog.Info("update the cover", "album", album.Title, "path", result.URI().Path())
 				showErrorIfAny(client.UpdateAlbumCover(album, result.URI().Path()))
 			}
 		}, getWindow())
@@ -125,7 +146,7 @@ func makeDeleteAlbumDialog(album *resource.Album) func() {
 	return func() {
 		dialog.ShowConfirm("", fmt.Sprintf("Do you want to delete %v?", album.Title), func(delete bool) {
 			if delete {
-				log.Printf("delete %v\n", album.Title)
+				libraryLog.Info("delete the album", "album", album.Title)
 				showErrorIfAny(client.DeleteAlbum(album))
 			}
 		}, getWindow())
@@ -9,18 +9,27 @@ import (
 	"meowyplayer.com/utility/pattern"
 )
 
-func newController() fyne.CanvasObject {
+func newController() (fyne.CanvasObject, error) {
 	coverView := cwidget.NewCoverView(fyne.NewSize(128.0, 128.0))
 	controller := cwidget.NewMediaMenu()
 	musicPlayer := client.NewMusicPlayer()
 	controller.Bind(musicPlayer)
-	go musicPlayer.Start(controller)
+	if err := musicPlayer.Start(controller); err != nil {
+		return nil, err
+	}
 
 	client.GetPlayListData().Attach(pattern.MakeCallback(func(p *resource.PlayList) {
 		coverView.SetAlbum(p.Album())
 		coverView.OnTapped = func(*fyne.PointEvent) { client.GetAlbumData().Set(p.Album()) }
 	}))
 	client.GetPlayListData().Attach(musicPlayer)
+	client.GetQueueData().Attach(pattern.MakeCallback(musicPlayer.CommandQueue))
+	client.GetStationData().Attach(pattern.MakeCallback(musicPlayer.CommandStation))
 
-	return container.NewBorder(nil, nil, coverView, nil, controller)
+	//the playback goes on, so the failures are told without a blocking dialog
+	client.GetPlaybackErrorData().Attach(pattern.MakeCallback(func(err error) {
+		fyne.CurrentApp().SendNotification(fyne.NewNotification("Playback error", err.Error()))
+	}))
+
+	return container.NewBorder(nil, nil, coverView, nil, controller), nil
 }
@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"meowyplayer.com/source/client"
+	"meowyplayer.com/source/resource"
+	"meowyplayer.com/source/ui/cwidget"
+)
+
+func newDownloadTab() *container.TabItem {
+	clearCompleted := cwidget.NewButtonWithIcon("Clear Completed", theme.ContentClearIcon(), func() { showErrorIfAny(client.ClearCompletedDownloads()) })
+	return container.NewTabItemWithIcon("Downloads", theme.DownloadIcon(), container.NewBorder(
+		container.NewBorder(nil, nil, widget.NewLabel("Parallel downloads"), clearCompleted, newDownloadConcurrencySelect()),
+		nil,
+		nil,
+		nil,
+		newDownloadViewList(),
+	))
+}
+
+func newDownloadConcurrencySelect() *widget.Select {
+	options := []string{"1", "2", "3", "4", "5"}
+	concurrencySelect := widget.NewSelect(options, nil)
+	concurrencySelect.SetSelected(strconv.Itoa(client.GetConfigData().Get().Downloads))
+	concurrencySelect.OnChanged = func(option string) {
+		downloads, _ := strconv.Atoi(option)
+		showErrorIfAny(client.SetDownloadConcurrency(downloads))
+	}
+	return concurrencySelect
+}
+
+func newDownloadViewList() *cwidget.ViewList[resource.DownloadJob] {
+	return cwidget.NewViewList[resource.DownloadJob](client.GetDownloadData(), container.NewVBox(),
+		func(job resource.DownloadJob) fyne.CanvasObject {
+			progress := widget.NewProgressBar()
+			if job.Total > 0 {
+				progress.SetValue(float64(job.Received) / float64(job.Total))
+			}
+			if job.State == resource.DownloadCompleted {
+				progress.SetValue(1)
+			}
+
+			buttons := container.NewHBox()
+			if !job.Finished() {
+				buttons.Add(cwidget.NewButtonWithIcon("", theme.CancelIcon(), func() { showErrorIfAny(client.CancelDownload(job.ID)) }))
+			}
+			if job.State == resource.DownloadFailed || job.State == resource.DownloadCancelled {
+				buttons.Add(cwidget.NewButtonWithIcon("", theme.ViewRefreshIcon(), func() { showErrorIfAny(client.RetryDownload(job.ID)) }))
+			}
+			if job.Finished() {
+				buttons.Add(cwidget.NewButtonWithIcon("", theme.DeleteIcon(), func() { showErrorIfAny(client.RemoveDownloads([]string{job.ID})) }))
+			}
+
+			title := widget.NewLabelWithStyle(fmt.Sprintf("%v → %v", job.Video.Title, job.AlbumTitle), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+			title.Truncation = fyne.TextTruncateEllipsis
+			return container.NewBorder(nil, nil, nil, buttons, container.NewVBox(title, progress, widget.NewLabel(downloadStatus(&job))))
+		},
+	)
+}
+
+func downloadStatus(job *resource.DownloadJob) string {
+	switch job.State {
+	case resource.DownloadRunning:
+		status := fmt.Sprintf("%v, %v/s", formatBytes(float64(job.Received)), formatBytes(job.Speed))
+		if job.Attempts > 1 {
+			status += fmt.Sprintf(", attempt %v", job.Attempts)
+		}
+		return status
+	case resource.DownloadFailed:
+		return fmt.Sprintf("failed after %v attempts: %v", job.Attempts, job.Error)
+	default:
+		return string(job.State)
+	}
+}
+
+func formatBytes(bytes float64) string {
+	units := []string{"B", "KB", "MB", "GB"}
+	unit := 0
+	for bytes >= 1024 && unit < len(units)-1 {
+		bytes /= 1024
+		unit++
+	}
+	return fmt.Sprintf("%.1f %v", bytes, units[unit])
+}
@@ -1,8 +1,13 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
@@ -13,7 +18,8 @@ import (
 	"meowyplayer.com/source/client"
 	"meowyplayer.com/source/resource"
 	"meowyplayer.com/source/ui/cwidget"
-	"meowyplayer.com/utility/network/downloader"
+	"meowyplayer.com/utility/cleaner"
+	"meowyplayer.com/utility/network"
 	"meowyplayer.com/utility/network/fileformat"
 	"meowyplayer.com/utility/network/scraper"
 	"meowyplayer.com/utility/pattern"
@@ -24,7 +30,7 @@ func showAddLocalMusicDialog() {
 		if err != nil {
 			showErrorIfAny(err)
 		} else if result != nil {
-			log.Printf("add %v from local to %v\n", result.URI().Name(), client.GetAlbumData().Get().Title)
+			libraryLog.Info("add the local music", "title", result.URI().Name())
 			showErrorIfAny(client.AddMusicFromURIReader(result))
 		}
 	}, getWindow())
@@ -33,62 +39,236 @@ func showAddLocalMusicDialog() {
 	fileReader.Show()
 }
 
-func newVideoResultViewList(dataSource pattern.Subject[[]fileformat.VideoResult], onDownload func(videoResult *fileformat.VideoResult)) *cwidget.ViewList[fileformat.VideoResult] {
+// the thumbnails are loaded in the background until the context is cancelled
+func newVideoResultViewList(dataSource pattern.Subject[[]fileformat.VideoResult], thumbnailContext func() context.Context, onDownload func(ctx context.Context, videoResult *fileformat.VideoResult) error) *cwidget.ViewList[fileformat.VideoResult] {
 	return cwidget.NewViewList[fileformat.VideoResult](dataSource, container.NewVBox(),
 		func(result fileformat.VideoResult) fyne.CanvasObject {
-			return cwidget.NewVideoResultView(&result, fyne.NewSize(128.0*1.61803398875, 128.0), onDownload)
+			view := cwidget.NewVideoResultView(&result, fyne.NewSize(128.0*1.61803398875, 128.0), onDownload)
+			if cleaned := client.CleanTitle(result.Title); cleaned.Title != result.Title {
+				view.SetPreview(describeCleanedTitle(&cleaned))
+			}
+			switch inLibrary, inAlbum := client.FindDownloadedVideo(result.VideoID); {
+			case inAlbum:
+				view.SetBadge("Already in this album")
+			case inLibrary:
+				view.SetBadge("Already in the library")
+			}
+			if result.ThumbnailURL != "" {
+				client.GetThumbnailLoader().Load(thumbnailContext(), result.ThumbnailURL, view.SetThumbnail)
+			}
+			return view
 		},
 	)
 }
 
+// the pages filtered down to nothing are skipped, a search gives up after this many in a row
+const maxFilteredPages = 5
+
+func describeCleanedTitle(cleaned *cleaner.Result) string {
+	if cleaned.Artist == "" {
+		return fmt.Sprintf("Saved as \"%v\"", cleaned.Title)
+	}
+	return fmt.Sprintf("Saved as \"%v\" by %v", cleaned.Title, cleaned.Artist)
+}
+
+// one search along with its pages, cancelled as a whole by the next search
+// the provider is fixed when the search starts, so that the results are downloaded from where they were found
+type searchSession struct {
+	ctx          context.Context
+	cancel       context.CancelFunc
+	provider     network.Provider //switched to the one resolving the pasted url, guarded by the provider lock
+	providerLock sync.Mutex
+	query        string
+	options      scraper.SearchOptions
+	queueURL     bool //whether a pasted url is queued, which is only done once when it is submitted
+	loading      sync.Mutex
+	ended        bool
+}
+
+func newSearchSession(provider network.Provider, query string, options scraper.SearchOptions, queueURL bool) *searchSession {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &searchSession{ctx: ctx, cancel: cancel, provider: provider, query: query, options: options, queueURL: queueURL}
+}
+
+// the search runs in the background while the results are downloaded from the ui
+func (s *searchSession) getProvider() network.Provider {
+	s.providerLock.Lock()
+	defer s.providerLock.Unlock()
+	return s.provider
+}
+
+func (s *searchSession) setProvider(provider network.Provider) {
+	s.providerLock.Lock()
+	defer s.providerLock.Unlock()
+	s.provider = provider
+}
+
+// the filters and the sort order next to the search bar
+func newSearchOptionsBar(options *scraper.SearchOptions, onChanged func()) fyne.CanvasObject {
+	durationSelect := newEnumSelect(scraper.Durations, &options.Duration, onChanged)
+	uploadDateSelect := newEnumSelect(scraper.UploadDates, &options.UploadDate, onChanged)
+	sortSelect := newEnumSelect(scraper.SortOrders, &options.Sort, onChanged)
+
+	channelEntry := widget.NewEntry()
+	channelEntry.SetPlaceHolder("Channel")
+	channelEntry.OnSubmitted = func(channel string) {
+		options.Channel = channel
+		onChanged()
+	}
+	return container.NewGridWithColumns(4, durationSelect, uploadDateSelect, sortSelect, channelEntry)
+}
+
+func newEnumSelect[T fmt.Stringer](values []T, value *T, onChanged func()) *widget.Select {
+	names := make([]string, len(values))
+	for i := range values {
+		names[i] = values[i].String()
+	}
+	enumSelect := widget.NewSelect(names, nil)
+	enumSelect.SetSelectedIndex(0)
+	enumSelect.OnChanged = func(string) {
+		*value = values[enumSelect.SelectedIndex()]
+		onChanged()
+	}
+	return enumSelect
+}
+
 func showAddOnlineMusicDialog() {
-	//scraper menu
-	var videoScraper scraper.VideoScraper
-	var musicDownloader downloader.MusicDownloader
+	providers := client.GetProviders(network.Search)
+	if len(providers) == 0 {
+		showErrorIfAny(fmt.Errorf("no search provider is enabled"))
+		return
+	}
 
-	//video result data list
+	//provider menu, the selection is only read on the ui goroutine
+	var provider network.Provider
+
+	//video result data list, a new search cancels the one in flight along with its thumbnails
+	//the downloads run in the background, so the current session is shared through an atomic pointer
+	options := scraper.SearchOptions{}
+	session := atomic.Pointer[searchSession]{}
+	session.Store(newSearchSession(provider, "", options, false))
 	videoResultData := pattern.Data[[]fileformat.VideoResult]{}
 	videoResultViewList := newVideoResultViewList(&videoResultData,
-		func(videoResult *fileformat.VideoResult) {
-			musicData, err := musicDownloader.Download(videoResult)
-			if err != nil {
+		func() context.Context { return session.Load().ctx },
+		func(ctx context.Context, videoResult *fileformat.VideoResult) error {
+			err := client.Download(ctx, session.Load().getProvider().Name, videoResult, client.GetAlbumData().Get())
+			if !errors.Is(err, context.Canceled) {
 				showErrorIfAny(err)
-				return
 			}
-			showErrorIfAny(client.AddMusicFromDownloader(videoResult, musicData))
+			return err
 		},
 	)
+	platformMenu := newProviderDropDown(providers, func(selected network.Provider) { provider = selected })
 
-	platformMenu := cwidget.NewDropDown("", resource.DefaultIcon())
-	platformMenu.Add("YouTube", resource.YouTubeIcon(), func() {
-		videoScraper = scraper.NewClipzagScraper()
-		musicDownloader = downloader.NewY2MateDownloader()
-	})
-	platformMenu.Add("BiliBili", resource.BiliBiliIcon(), func() {
-		fmt.Println("not implemented...")
-	})
-	platformMenu.Select(0)
+	//the next page is loaded once the results are scrolled to the end
+	loadNextPage := func(current *searchSession) {
+		if !current.loading.TryLock() {
+			return
+		}
+		defer current.loading.Unlock()
+		if current.ended {
+			return
+		}
 
-	//search bar
-	searchBar := widget.NewEntry()
-	searchBar.SetPlaceHolder("Search Video")
-	searchBar.ActionItem = cwidget.NewButtonWithIcon("", theme.SearchIcon(), func() { searchBar.OnSubmitted(searchBar.Text) })
-	searchBar.OnSubmitted = func(title string) {
-		result, err := videoScraper.Search(title)
+		var result []fileformat.VideoResult
+		var err error
+		for skipped := 0; len(result) == 0 && !current.ended && err == nil; skipped++ {
+			current.options.Page++
+			if current.options.Page == 1 {
+				result, current.ended, err = searchVideo(current)
+			} else {
+				result, err = current.getProvider().VideoScraper.Search(current.ctx, current.query, current.options)
+			}
+			//an empty page is the last one, unless the filters have emptied it
+			current.ended = current.ended || (len(result) == 0 && (!current.options.Filtered() || skipped+1 >= maxFilteredPages))
+		}
+		if current.ctx.Err() != nil {
+			return
+		}
 		if err != nil {
-			showErrorIfAny(err)
+			current.ended = true
+			searched := current.getProvider()
+			showSearchError(&searched, err)
 			return
 		}
-		videoResultData.Set(result)
+		videoResultData.Set(append(slices.Clip(videoResultData.Get()), result...))
+	}
+	videoResultViewList.SetOnEndReached(func() { go loadNextPage(session.Load()) })
+	search := func(query string, queueURL bool) {
+		session.Load().cancel()
+		session.Store(newSearchSession(provider, query, options, queueURL))
+		videoResultData.Set(nil)
+		go loadNextPage(session.Load())
+	}
+
+	//search bar
+	searchBar := cwidget.NewCompletionEntry(client.SuggestSearch)
+	searchBar.SetPlaceHolder("Search Video or Paste URL")
+	searchBar.ActionItem = cwidget.NewButtonWithIcon("", theme.SearchIcon(), func() { searchBar.OnSubmitted(searchBar.Text) })
+	searchBar.OnSubmitted = func(query string) {
+		showErrorIfAny(client.AddSearchHistory(query))
+		search(query, true)
 	}
 
+	//changing an option searches again, without queuing the pasted url once more
+	optionsBar := newSearchOptionsBar(&options, func() {
+		if strings.TrimSpace(searchBar.Text) != "" {
+			search(searchBar.Text, false)
+		}
+	})
+
 	onlineMusicDialog := dialog.NewCustom("", "O", container.NewBorder(
-		container.NewBorder(nil, nil, platformMenu, nil, searchBar),
+		container.NewVBox(container.NewBorder(nil, nil, platformMenu, nil, searchBar), optionsBar),
 		nil,
 		nil,
 		nil,
 		videoResultViewList,
 	), getWindow())
+	onlineMusicDialog.SetOnClosed(func() { session.Load().cancel() })
 	onlineMusicDialog.Resize(getWindow().Canvas().Size())
 	onlineMusicDialog.Show()
 }
+
+// a pasted url (or bare id) is resolved and queued right away, anything else is searched with the selected provider
+// the selected provider is preferred for the url, if it recognizes it
+// return whether there are no more pages to the result
+func searchVideo(session *searchSession) ([]fileformat.VideoResult, bool, error) {
+	if urlProvider, videoID, ok := client.GetURLProvider(session.query, session.getProvider().Name); ok {
+		video, err := urlProvider.VideoResolver.ResolveVideo(session.ctx, videoID)
+		if err == nil {
+			//the result is downloaded from where it was resolved, it is listed only after this
+			session.setProvider(urlProvider)
+		}
+		switch {
+		case err == nil && !session.queueURL:
+			return []fileformat.VideoResult{*video}, true, nil
+		case err == nil:
+			networkLog.Info("download the video", "title", video.Title, "provider", urlProvider.Name)
+			_, err := client.QueueDownload(urlProvider.Name, video, client.GetAlbumData().Get())
+			return []fileformat.VideoResult{*video}, true, err
+		case strings.Contains(session.query, "/") || session.ctx.Err() != nil:
+			return nil, true, err
+		}
+		//a single word that merely looks like an id is searched instead
+	}
+	result, err := session.getProvider().VideoScraper.Search(session.ctx, session.query, session.options)
+	return result, false, err
+}
+
+// a broken scraper is told apart from the network errors, so that the users know to switch provider
+func showSearchError(provider *network.Provider, err error) {
+	if errors.Is(err, scraper.ErrLayoutChanged) {
+		err = fmt.Errorf("%v can't be searched right now, the site has probably changed its layout. Please try another provider.\n\n%w", provider.Name, err)
+	}
+	showErrorIfAny(err)
+}
+
+// list the providers in the given order, the first one is selected
+func newProviderDropDown(providers []network.Provider, onSelected func(network.Provider)) *cwidget.DropDown {
+	dropDown := cwidget.NewDropDown("", resource.DefaultIcon())
+	for _, provider := range providers {
+		dropDown.Add(provider.Name, provider.Icon, func() { onSelected(provider) })
+	}
+	dropDown.Select(0)
+	return dropDown
+}
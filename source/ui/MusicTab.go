@@ -2,7 +2,8 @@ package ui
 
 import (
 	"fmt"
-	"log"
+	"net/url"
+	"slices"
 	"strings"
 
 	"fyne.io/fyne/v2"
@@ -20,44 +21,236 @@ import (
 func newMusicTab() *container.TabItem {
 	data := cbinding.MakeMusicDataList()
 	client.GetAlbumData().Attach(&data)
+	client.GetAlbumRefreshData().Attach(&data)
+
+	selection := cbinding.MakeSelection(func(m resource.Music) string { return m.Title })
+	data.Attach(&selection)
+	viewList := newMusicViewList(&data, &selection)
 
 	searchBar := newMusicSearchBar(&data)
-	client.GetAlbumData().Attach(pattern.MakeCallback(func(*resource.Album) { searchBar.SetText("") }))
+	client.GetAlbumData().Attach(pattern.MakeCallback(func(*resource.Album) {
+		searchBar.SetText("")
+		selection.Clear()
+	}))
+
+	//select all the displayed music
+	getWindow().Canvas().AddShortcut(&fyne.ShortcutSelectAll{}, func(fyne.Shortcut) {
+		selection.SelectAll()
+		updateMusicSelection(viewList, &selection)
+	})
 
 	musicAdderLocal := cwidget.NewButtonWithIcon("", theme.FolderOpenIcon(), showAddLocalMusicDialog)
 	musicAdderOnline := cwidget.NewButtonWithIcon("", resource.MusicAdderOnlineIcon(), showAddOnlineMusicDialog)
 
-	return container.NewTabItemWithIcon("Music", resource.MusicTabIcon(), container.NewBorder(
+	//the podcast albums are filled from their episodes
+	episodes := cwidget.NewButtonWithIcon("", theme.ListIcon(), func() { showEpisodesDialog(client.GetAlbumData().Get()) })
+	episodes.Hide()
+	client.GetAlbumData().Attach(pattern.MakeCallback(func(album *resource.Album) {
+		if album != nil && album.Feed != nil {
+			episodes.Show()
+		} else {
+			episodes.Hide()
+		}
+	}))
+
+	tab := container.NewTabItemWithIcon("Music", resource.MusicTabIcon(), container.NewBorder(
 		container.NewBorder(
 			nil,
-			container.NewGridWithRows(1, newMusicTitleButton(&data, "Title"), newMusicDateButton(&data, "Date")),
+			container.NewGridWithRows(1, newMusicTitleButton(&data, "Title"), newMusicDateButton(&data, "Date"), newMusicCustomButton(&data, "Custom")),
 			nil,
-			container.NewGridWithRows(1, musicAdderLocal, musicAdderOnline),
+			container.NewGridWithRows(1, episodes, musicAdderLocal, musicAdderOnline),
 			searchBar,
 		),
 		nil,
 		nil,
 		nil,
-		newMusicViewList(&data),
+		viewList,
 	))
+	dropHandlers[tab] = func(_ fyne.Position, uris []fyne.URI) {
+		libraryLog.Info("drop onto the album", "count", len(uris))
+		showErrorIfAny(client.AddMusicFromPaths(client.GetAlbumData().Get(), getLocalPaths(uris)))
+	}
+	return tab
 }
 
-func newMusicViewList(data *cbinding.MusicDataList) *cwidget.ViewList[resource.Music] {
-	return cwidget.NewViewList[resource.Music](data, container.NewVBox(),
+func newMusicViewList(data *cbinding.MusicDataList, selection *cbinding.Selection[resource.Music]) *cwidget.ViewList[resource.Music] {
+	var viewList *cwidget.ViewList[resource.Music]
+	viewList = cwidget.NewViewList[resource.Music](data, container.NewVBox(),
 		func(music resource.Music) fyne.CanvasObject {
 			view := cwidget.NewMusicView(&music)
-			view.OnTapped = func(*fyne.PointEvent) { client.GetPlayListData().Set(resource.NewPlayList(data.GetAlbum(), &music)) }
-			view.OnTappedSecondary = func(*fyne.PointEvent) { showDeleteMusicDialog(&music) }
+			view.SetSelected(selection.Contains(music))
+			view.OnTapped = func(*fyne.PointEvent) {
+				switch {
+				case view.Modifier()&fyne.KeyModifierShift != 0:
+					selection.SelectRange(music)
+				case view.Modifier()&fyne.KeyModifierShortcutDefault != 0:
+					selection.Toggle(music)
+				default:
+					selection.Clear()
+					playMusic(data.GetAlbum(), &music)
+				}
+				updateMusicSelection(viewList, selection)
+			}
+			view.OnTappedSecondary = func(event *fyne.PointEvent) {
+				if !selection.Contains(music) {
+					selection.Select(music)
+					updateMusicSelection(viewList, selection)
+				}
+				canvas := fyne.CurrentApp().Driver().CanvasForObject(view)
+				showMusicMenu(selection.Items(), canvas, event.AbsolutePosition)
+			}
+			view.OnDropped = func(pos fyne.Position) {
+				if target, ok := viewList.ItemAt(pos); ok && target.Title != music.Title {
+					libraryLog.Info("reorder the music", "title", music.Title, "target", target.Title)
+					data.SetSorter(customOrder)
+					showErrorIfAny(client.ReorderMusic(&music, &target))
+				}
+			}
 			return view
 		},
 	)
+	return viewList
+}
+
+func updateMusicSelection(viewList *cwidget.ViewList[resource.Music], selection *cbinding.Selection[resource.Music]) {
+	viewList.UpdateViews(func(music resource.Music, view fyne.CanvasObject) {
+		view.(*cwidget.MusicView).SetSelected(selection.Contains(music))
+	})
 }
 
-func showDeleteMusicDialog(music *resource.Music) {
-	dialog.ShowConfirm("", fmt.Sprintf("Do you want to delete %v?", music.Title), func(delete bool) {
+func showMusicMenu(musicList []resource.Music, canvas fyne.Canvas, pos fyne.Position) {
+	move := fyne.NewMenuItem("Move to", nil)
+	move.ChildMenu = newAlbumMenu(func(album *resource.Album) {
+		libraryLog.Info("move the music", "count", len(musicList), "album", album.Title)
+		showErrorIfAny(client.MoveMusic(musicList, album))
+	})
+	copy := fyne.NewMenuItem("Copy to", nil)
+	copy.ChildMenu = newAlbumMenu(func(album *resource.Album) {
+		libraryLog.Info("copy the music", "count", len(musicList), "album", album.Title)
+		showErrorIfAny(client.CopyMusic(musicList, album))
+	})
+	queue := fyne.NewMenuItem("Add to play list", func() { client.QueueMusic(musicList) })
+	delete := fyne.NewMenuItem("Remove", func() { showDeleteMusicDialog(musicList) })
+
+	//the downloaded music can be traced back to its source
+	redownload := fyne.NewMenuItem("Download again", func() {
+		networkLog.Info("download the music again", "count", len(musicList), "album", client.GetAlbumData().Get().Title)
+		showErrorIfAny(client.RedownloadMusic(client.GetAlbumData().Get(), musicList))
+	})
+	redownload.Disabled = !slices.ContainsFunc(musicList, func(m resource.Music) bool { return m.Source != nil })
+	openSource := fyne.NewMenuItem("Open source", nil)
+	channel := fyne.NewMenuItem("More from this channel", nil)
+	if source := musicList[0].Source; len(musicList) == 1 && source != nil {
+		openSource.Action = func() { showErrorIfAny(openURL(source.URL)) }
+		if source.ChannelID != "" {
+			channel.Action = func() { showChannelMusicDialog(source) }
+		}
+	}
+	openSource.Disabled = openSource.Action == nil
+	channel.Disabled = channel.Action == nil
+
+	clean := fyne.NewMenuItem("Clean titles", func() { showCleanTitlesDialog(musicList) })
+
+	menu := fyne.NewMenu("", move, copy, queue, delete, clean, fyne.NewMenuItemSeparator(), redownload, openSource, channel)
+
+	//the episodes of a podcast remember whether they have been played
+	if client.GetAlbumData().Get().Feed != nil {
+		played := fyne.NewMenuItem("Mark as played", func() { showErrorIfAny(client.SetEpisodesPlayed(musicList, true)) })
+		unplayed := fyne.NewMenuItem("Mark as unplayed", func() { showErrorIfAny(client.SetEpisodesPlayed(musicList, false)) })
+		menu.Items = append(menu.Items, fyne.NewMenuItemSeparator(), played, unplayed)
+	}
+	widget.ShowPopUpMenuAtPosition(menu, canvas, pos)
+}
+
+// preview the renames before applying the title rules
+func showCleanTitlesDialog(musicList []resource.Music) {
+	changes := []string{}
+	for _, music := range musicList {
+		if cleaned, _ := client.CleanMusicTitle(&music); cleaned.Title != music.Title || cleaned.Artist != music.Artist {
+			change := fmt.Sprintf("%v\n→ %v", music.Title, cleaned.Title)
+			if cleaned.Artist != "" {
+				change += " by " + cleaned.Artist
+			}
+			changes = append(changes, change)
+		}
+	}
+	if len(changes) == 0 {
+		dialog.ShowInformation("", "The titles are already clean.", getWindow())
+		return
+	}
+
+	list := widget.NewList(
+		func() int { return len(changes) },
+		func() fyne.CanvasObject { return widget.NewLabel("\n") },
+		func(id widget.ListItemID, object fyne.CanvasObject) { object.(*widget.Label).SetText(changes[id]) },
+	)
+	cleanDialog := dialog.NewCustomConfirm(fmt.Sprintf("Clean %v titles?", len(changes)), "Clean", "Cancel", list, func(clean bool) {
+		if clean {
+			libraryLog.Info("clean the titles", "count", len(changes), "album", client.GetAlbumData().Get().Title)
+			showErrorIfAny(client.CleanMusicTitles(musicList))
+		}
+	}, getWindow())
+	cleanDialog.Resize(getWindow().Canvas().Size().Subtract(fyne.NewSize(128.0, 128.0)))
+	cleanDialog.Show()
+}
+
+func openURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	return fyne.CurrentApp().OpenURL(u)
+}
+
+// list the music downloaded from the channel across the albums, tapping one plays the channel from it
+func showChannelMusicDialog(source *resource.Source) {
+	channel := resource.Album{Title: source.ChannelTitle}
+	albumTitles := []string{}
+	for _, album := range client.FindMusicByChannel(source.ChannelID) {
+		for _, music := range album.MusicList {
+			channel.MusicList.PushBack(music)
+			albumTitles = append(albumTitles, album.Title)
+		}
+	}
+
+	var channelDialog dialog.Dialog
+	list := widget.NewList(
+		func() int { return len(channel.MusicList) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, object fyne.CanvasObject) {
+			object.(*widget.Label).SetText(fmt.Sprintf("%v | %v", albumTitles[id], channel.MusicList[id].Description()))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		playMusic(&channel, &channel.MusicList[id])
+		channelDialog.Hide()
+	}
+	channelDialog = dialog.NewCustom(source.ChannelTitle, "Close", list, getWindow())
+	channelDialog.Resize(getWindow().Canvas().Size().Subtract(fyne.NewSize(128.0, 128.0)))
+	channelDialog.Show()
+}
+
+// list all the albums other than the current one
+func newAlbumMenu(onSelected func(*resource.Album)) *fyne.Menu {
+	menu := fyne.NewMenu("")
+	for _, album := range client.GetCollectionData().Get().Albums {
+		album := album
+		if album.Title != client.GetAlbumData().Get().Title {
+			menu.Items = append(menu.Items, fyne.NewMenuItem(album.Title, func() { onSelected(&album) }))
+		}
+	}
+	return menu
+}
+
+func showDeleteMusicDialog(musicList []resource.Music) {
+	message := fmt.Sprintf("Do you want to delete %v music?", len(musicList))
+	if len(musicList) == 1 {
+		message = fmt.Sprintf("Do you want to delete %v?", musicList[0].Title)
+	}
+	dialog.ShowConfirm("", message, func(delete bool) {
 		if delete {
-			log.Printf("delete %v from the album %v\n", music.Title, client.GetAlbumData().Get().Title)
-			showErrorIfAny(client.DeleteMusic(music))
+			libraryLog.Info("delete the music", "count", len(musicList), "album", client.GetAlbumData().Get().Title)
+			showErrorIfAny(client.DeleteMusic(musicList))
 		}
 	}, getWindow())
 }
@@ -96,3 +289,21 @@ func newMusicDateButton(data *cbinding.MusicDataList, title string) *widget.Butt
 	button.OnTapped()
 	return button
 }
+
+// make data follow the custom order of the album
+func newMusicCustomButton(data *cbinding.MusicDataList, title string) *widget.Button {
+	return cwidget.NewButton(title, func() { data.SetSorter(customOrder) })
+}
+
+func customOrder(resource.Music, resource.Music) bool {
+	return false
+}
+
+func playMusic(album *resource.Album, music *resource.Music) {
+	playList, err := resource.NewPlayList(album, music)
+	if err != nil {
+		showErrorIfAny(err)
+		return
+	}
+	client.GetPlayListData().Set(playList)
+}
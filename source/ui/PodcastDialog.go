@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"meowyplayer.com/source/client"
+	"meowyplayer.com/source/resource"
+	"meowyplayer.com/source/ui/cwidget"
+	"meowyplayer.com/utility/network/fileformat"
+	"meowyplayer.com/utility/pattern"
+)
+
+func showAddPodcastDialog() {
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder("RSS / Atom Feed URL")
+	podcastDialog := dialog.NewCustomConfirm("Subscribe to podcast:", "Subscribe", "Cancel", entry, func(subscribe bool) {
+		if !subscribe {
+			return
+		}
+		libraryLog.Info("subscribe to the podcast", "url", entry.Text)
+		go func() {
+			album, err := client.AddPodcast(context.Background(), entry.Text)
+			if err != nil {
+				showErrorIfAny(err)
+				return
+			}
+			showEpisodesDialog(&album)
+		}()
+	}, getWindow())
+	podcastDialog.Resize(fyne.NewSize(512.0, podcastDialog.MinSize().Height))
+	podcastDialog.Show()
+}
+
+// list the episodes of the podcast, the downloaded ones are added to its album
+func showEpisodesDialog(album *resource.Album) {
+	title := album.Title
+	findAlbum := func() (resource.Album, bool) {
+		albums := client.GetCollectionData().Get().Albums
+		index := slices.IndexFunc(albums, func(a resource.Album) bool { return a.Title == title && a.Feed != nil })
+		if index == -1 {
+			return resource.Album{}, false
+		}
+		return albums[index], true
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	episodeData := pattern.Data[[]fileformat.VideoResult]{}
+	episodeViewList := newVideoResultViewList(&episodeData,
+		func() context.Context { return ctx },
+		func(ctx context.Context, episode *fileformat.VideoResult) error {
+			current, ok := findAlbum()
+			if !ok {
+				return fmt.Errorf("%w: %q", resource.ErrAlbumNotFound, title)
+			}
+			err := client.Download(ctx, client.PodcastProvider, episode, &current)
+			if !errors.Is(err, context.Canceled) {
+				showErrorIfAny(err)
+			}
+			return err
+		},
+	)
+	status := widget.NewLabel("")
+	update := func() {
+		if current, ok := findAlbum(); ok {
+			status.SetText(fmt.Sprintf("%v episodes, refreshed %v", len(current.Feed.Episodes), current.Feed.Refreshed.Format(time.DateTime)))
+			episodeData.Set(current.Feed.Episodes)
+		}
+	}
+
+	refresh := cwidget.NewButtonWithIcon("Refresh", theme.ViewRefreshIcon(), nil)
+	refresh.OnTapped = func() {
+		refresh.Disable()
+		status.SetText("refreshing...")
+		go func() {
+			defer refresh.Enable()
+			current, _ := findAlbum()
+			if err := client.RefreshPodcast(ctx, &current); ctx.Err() == nil {
+				showErrorIfAny(err)
+				update()
+			}
+		}()
+	}
+
+	episodeDialog := dialog.NewCustom(title, "Close", container.NewBorder(
+		container.NewBorder(nil, nil, nil, refresh, status),
+		nil,
+		nil,
+		nil,
+		episodeViewList,
+	), getWindow())
+	episodeDialog.SetOnClosed(cancel)
+	episodeDialog.Resize(getWindow().Canvas().Size())
+	episodeDialog.Show()
+	update()
+}
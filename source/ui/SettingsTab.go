@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"meowyplayer.com/source/client"
+	"meowyplayer.com/source/resource"
+	"meowyplayer.com/source/ui/cwidget"
+	"meowyplayer.com/utility/cleaner"
+	"meowyplayer.com/utility/network"
+	"meowyplayer.com/utility/network/downloader"
+	"meowyplayer.com/utility/pattern"
+)
+
+func newSettingsTab() *container.TabItem {
+	return container.NewTabItemWithIcon("Settings", theme.SettingsIcon(), container.NewBorder(
+		container.NewVBox(
+			widget.NewLabel("Network"), newNetworkForm(),
+			widget.NewLabel("Title Cleanup"), newTitleRulesForm(),
+			widget.NewLabel("yt-dlp"), newYtDlpForm(),
+			widget.NewLabel("Providers"),
+		),
+		nil,
+		nil,
+		nil,
+		newProviderViewList(),
+	))
+}
+
+func newNetworkForm() *widget.Form {
+	labels := []string{"10 seconds", "30 seconds", "60 seconds", "None"}
+	timeouts := []time.Duration{10 * time.Second, 30 * time.Second, 60 * time.Second, 0}
+	network := client.GetConfigData().Get().Network
+
+	timeoutSelect := widget.NewSelect(labels, nil)
+	if index := slices.Index(timeouts, network.Timeout); index != -1 {
+		timeoutSelect.SetSelectedIndex(index)
+	}
+	userAgentEntry := widget.NewEntry()
+	userAgentEntry.SetText(network.UserAgent)
+	proxyEntry := widget.NewEntry()
+	proxyEntry.SetPlaceHolder("http://host:port")
+	proxyEntry.SetText(network.Proxy)
+
+	form := widget.NewForm(
+		widget.NewFormItem("Timeout", timeoutSelect),
+		widget.NewFormItem("User Agent", userAgentEntry),
+		widget.NewFormItem("Proxy", proxyEntry),
+	)
+	form.SubmitText = "Apply"
+	form.OnSubmit = func() {
+		network.UserAgent = userAgentEntry.Text
+		network.Proxy = proxyEntry.Text
+		if index := timeoutSelect.SelectedIndex(); index != -1 {
+			network.Timeout = timeouts[index]
+		}
+		showErrorIfAny(client.SetNetworkConfig(network))
+	}
+	return form
+}
+
+// the removal rules are regular expressions, one per line
+func newTitleRulesForm() *widget.Form {
+	rules := client.GetConfigData().Get().TitleRules
+
+	removalEntry := widget.NewMultiLineEntry()
+	removalEntry.SetMinRowsVisible(3)
+	removalEntry.SetText(strings.Join(rules.Removals, "\n"))
+	splitCheck := widget.NewCheck("Read \"Artist - Title\"", nil)
+	splitCheck.SetChecked(rules.SplitArtist)
+	caseNames := []string{}
+	for _, c := range cleaner.Cases {
+		caseNames = append(caseNames, c.String())
+	}
+	caseSelect := widget.NewSelect(caseNames, nil)
+	caseSelect.SetSelectedIndex(int(rules.Case))
+
+	form := widget.NewForm(
+		widget.NewFormItem("Remove", removalEntry),
+		widget.NewFormItem("Artist", splitCheck),
+		widget.NewFormItem("Case", caseSelect),
+	)
+	form.SubmitText = "Apply"
+	form.OnSubmit = func() {
+		rules.Removals = slices.DeleteFunc(strings.Split(removalEntry.Text, "\n"), func(r string) bool { return strings.TrimSpace(r) == "" })
+		rules.SplitArtist = splitCheck.Checked
+		rules.Case = cleaner.Cases[max(caseSelect.SelectedIndex(), 0)]
+		showErrorIfAny(client.SetTitleRules(rules))
+	}
+	return form
+}
+
+// the audio is extracted by the locally installed yt-dlp and ffmpeg
+func newYtDlpForm() *widget.Form {
+	options := client.GetConfigData().Get().YtDlp
+
+	formatSelect := widget.NewSelect(downloader.YtDlpFormats, nil)
+	formatSelect.SetSelected(options.Format)
+	bitrateNames := []string{}
+	for _, bitrate := range downloader.YtDlpBitrates {
+		bitrateNames = append(bitrateNames, fmt.Sprintf("%v kbps", bitrate))
+	}
+	bitrateSelect := widget.NewSelect(bitrateNames, nil)
+	bitrateSelect.SetSelectedIndex(slices.Index(downloader.YtDlpBitrates, options.Bitrate))
+
+	form := widget.NewForm(
+		widget.NewFormItem("Format", formatSelect),
+		widget.NewFormItem("Bitrate", bitrateSelect),
+	)
+	form.SubmitText = "Apply"
+	form.OnSubmit = func() {
+		options.Format = formatSelect.Selected
+		if index := bitrateSelect.SelectedIndex(); index != -1 {
+			options.Bitrate = downloader.YtDlpBitrates[index]
+		}
+		showErrorIfAny(client.SetYtDlpOptions(options))
+	}
+	return form
+}
+
+func newPodcastForm() *widget.Form {
+	labels := []string{"Every hour", "Every 6 hours", "Every day", "Never"}
+	periods := []time.Duration{time.Hour, 6 * time.Hour, 24 * time.Hour, 0}
+
+	refreshSelect := widget.NewSelect(labels, nil)
+	if index := slices.Index(periods, client.GetConfigData().Get().PodcastRefresh); index != -1 {
+		refreshSelect.SetSelectedIndex(index)
+	}
+	refreshSelect.OnChanged = func(string) { showErrorIfAny(client.SetPodcastRefresh(periods[refreshSelect.SelectedIndex()])) }
+	return widget.NewForm(widget.NewFormItem("Refresh", refreshSelect))
+}
+
+// the providers are listed in the order they appear in the drop downs
+func newProviderViewList() *cwidget.ViewList[resource.ProviderSetting] {
+	data := pattern.Data[[]resource.ProviderSetting]{}
+	viewList := cwidget.NewViewList[resource.ProviderSetting](&data, container.NewVBox(),
+		func(setting resource.ProviderSetting) fyne.CanvasObject {
+			provider, _ := network.Lookup(setting.Name)
+			enabled := widget.NewCheck("", func(enabled bool) { showErrorIfAny(client.SetProviderEnabled(setting.Name, enabled)) })
+			enabled.Checked = setting.Enabled
+			up := cwidget.NewButtonWithIcon("", theme.MoveUpIcon(), func() { showErrorIfAny(client.MoveProvider(setting.Name, -1)) })
+			down := cwidget.NewButtonWithIcon("", theme.MoveDownIcon(), func() { showErrorIfAny(client.MoveProvider(setting.Name, 1)) })
+			return container.NewBorder(nil, nil, container.NewHBox(enabled, widget.NewIcon(provider.Icon)), container.NewHBox(up, down), widget.NewLabel(setting.Name))
+		},
+	)
+	client.GetConfigData().Attach(pattern.MakeCallback(func(*resource.Config) { data.Set(client.GetProviderSettings()) }))
+	data.Set(client.GetProviderSettings())
+	return viewList
+}
@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"meowyplayer.com/source/client"
+	"meowyplayer.com/source/resource"
+	"meowyplayer.com/source/ui/cbinding"
+	"meowyplayer.com/source/ui/cwidget"
+)
+
+func newStationTab() *container.TabItem {
+	data := cbinding.MakeStationDataList()
+	data.SetSorter(func(s1, s2 resource.Station) bool { return strings.ToLower(s1.Title) < strings.ToLower(s2.Title) })
+	client.GetCollectionData().Attach(&data)
+
+	stationAdder := cwidget.NewButtonWithIcon("", theme.ContentAddIcon(), showAddStationDialog)
+	return container.NewTabItemWithIcon("Stations", theme.VolumeUpIcon(), container.NewBorder(
+		container.NewBorder(nil, nil, nil, stationAdder, newStationSearchBar(&data)),
+		nil,
+		nil,
+		nil,
+		newStationViewList(&data),
+	))
+}
+
+// tapping a station plays it
+func newStationViewList(data *cbinding.StationDataList) *cwidget.ViewList[resource.Station] {
+	return cwidget.NewViewList[resource.Station](data, container.NewVBox(),
+		func(station resource.Station) fyne.CanvasObject {
+			play := cwidget.NewButtonWithIcon("", theme.MediaPlayIcon(), func() {
+				playerLog.Info("play the station", "station", station.Title)
+				client.GetStationData().Set(&station)
+			})
+			rename := cwidget.NewButtonWithIcon("", theme.DocumentCreateIcon(), func() { showRenameStationDialog(&station) })
+			delete := cwidget.NewButtonWithIcon("", theme.DeleteIcon(), func() { showDeleteStationDialog(&station) })
+			return container.NewBorder(nil, nil, play, container.NewHBox(rename, delete), widget.NewLabel(station.Description()))
+		},
+	)
+}
+
+func newStationSearchBar(data *cbinding.StationDataList) *widget.Entry {
+	entry := widget.NewEntry()
+	entry.OnChanged = func(title string) {
+		title = strings.ToLower(title)
+		data.SetFilter(func(s resource.Station) bool {
+			return strings.Contains(strings.ToLower(s.Title), title)
+		})
+	}
+	return entry
+}
+
+func showAddStationDialog() {
+	titleEntry := widget.NewEntry()
+	titleEntry.SetPlaceHolder("Optional")
+	urlEntry := widget.NewEntry()
+	urlEntry.SetPlaceHolder("http://host:port/stream")
+	items := []*widget.FormItem{widget.NewFormItem("Title", titleEntry), widget.NewFormItem("URL", urlEntry)}
+	stationDialog := dialog.NewForm("Add station", "Add", "Cancel", items, func(add bool) {
+		if add {
+			libraryLog.Info("add the station", "url", urlEntry.Text)
+			showErrorIfAny(client.AddStation(titleEntry.Text, urlEntry.Text))
+		}
+	}, getWindow())
+	stationDialog.Resize(fyne.NewSize(512.0, stationDialog.MinSize().Height))
+	stationDialog.Show()
+}
+
+func showRenameStationDialog(station *resource.Station) {
+	entry := widget.NewEntry()
+	entry.SetText(station.Title)
+	dialog.ShowCustomConfirm("Enter title:", "Confirm", "Cancel", entry, func(rename bool) {
+		if rename {
+			libraryLog.Info("rename the station", "station", station.Title, "title", entry.Text)
+			showErrorIfAny(client.UpdateStationTitle(station, entry.Text))
+		}
+	}, getWindow())
+}
+
+func showDeleteStationDialog(station *resource.Station) {
+	dialog.ShowConfirm("", fmt.Sprintf("Do you want to delete %v?", station.Title), func(delete bool) {
+		if delete {
+			libraryLog.Info("delete the station", "station", station.Title)
+			showErrorIfAny(client.DeleteStation(station))
+		}
+	}, getWindow())
+}
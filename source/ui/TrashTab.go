@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"meowyplayer.com/source/client"
+	"meowyplayer.com/source/resource"
+	"meowyplayer.com/source/ui/cbinding"
+	"meowyplayer.com/source/ui/cwidget"
+)
+
+func newTrashTab() *container.TabItem {
+	data := cbinding.MakeTrashDataList()
+	data.SetSorter(func(t1, t2 resource.Trash) bool { return t1.Date.After(t2.Date) })
+	client.GetCollectionData().Attach(&data)
+
+	return container.NewTabItemWithIcon("Trash", theme.DeleteIcon(), container.NewBorder(
+		container.NewBorder(nil, nil, widget.NewLabel("Keep deleted items for"), nil, newTrashRetentionSelect()),
+		nil,
+		nil,
+		nil,
+		newTrashViewList(&data),
+	))
+}
+
+func newTrashViewList(data *cbinding.TrashDataList) *cwidget.ViewList[resource.Trash] {
+	return cwidget.NewViewList[resource.Trash](data, container.NewVBox(),
+		func(trash resource.Trash) fyne.CanvasObject {
+			restore := cwidget.NewButtonWithIcon("", theme.ContentUndoIcon(), func() {
+				libraryLog.Info("restore the trash", "album", trash.Album.Title)
+				showErrorIfAny(client.RestoreTrash(&trash))
+			})
+			purge := cwidget.NewButtonWithIcon("", theme.DeleteIcon(), func() { showPurgeTrashDialog(&trash) })
+			return container.NewBorder(nil, nil, nil, container.NewHBox(restore, purge), widget.NewLabel(trash.Description()))
+		},
+	)
+}
+
+func newTrashRetentionSelect() *widget.Select {
+	labels := []string{"1 day", "7 days", "30 days", "90 days", "Forever"}
+	retentions := []time.Duration{24 * time.Hour, 7 * 24 * time.Hour, 30 * 24 * time.Hour, 90 * 24 * time.Hour, 0}
+
+	retentionSelect := widget.NewSelect(labels, nil)
+	if index := slices.Index(retentions, client.GetConfigData().Get().TrashRetention); index != -1 {
+		retentionSelect.SetSelectedIndex(index)
+	}
+	retentionSelect.OnChanged = func(string) {
+		showErrorIfAny(client.SetTrashRetention(retentions[retentionSelect.SelectedIndex()]))
+	}
+	return retentionSelect
+}
+
+func showPurgeTrashDialog(trash *resource.Trash) {
+	dialog.ShowConfirm("", fmt.Sprintf("Do you want to delete %v permanently? This cannot be undone.", trash.Album.Title), func(purge bool) {
+		if purge {
+			libraryLog.Info("purge the trash", "album", trash.Album.Title)
+			showErrorIfAny(client.PurgeTrash(trash))
+		}
+	}, getWindow())
+}
@@ -1,6 +1,12 @@
 package ui
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/container"
@@ -9,18 +15,43 @@ import (
 	"fyne.io/fyne/v2/theme"
 	"meowyplayer.com/source/client"
 	"meowyplayer.com/source/resource"
+	"meowyplayer.com/utility/audio"
+	"meowyplayer.com/utility/logger"
+	"meowyplayer.com/utility/network"
+	"meowyplayer.com/utility/network/downloader"
+	"meowyplayer.com/utility/network/httpclient"
+	"meowyplayer.com/utility/network/scraper"
 	"meowyplayer.com/utility/pattern"
 )
 
-func NewMainWindow() fyne.Window {
-	const windowTitle = "MeowyPlayer"
-	windowSize := fyne.NewSize(770.0, 650.0)
+var playerLog = logger.Subsystem(logger.Player)
+var networkLog = logger.Subsystem(logger.Network)
+var libraryLog = logger.Subsystem(logger.Library)
 
-	//create window
+var dropHandlers = map[*container.TabItem]func(fyne.Position, []fyne.URI){}
+
+const windowTitle = "MeowyPlayer"
+
+// the app is made once, either for the main window or for telling the fatal error
+var currentApp = sync.OnceValue(func() fyne.App {
 	fyne.SetCurrentApp(app.NewWithID(windowTitle))
 	fyne.CurrentApp().Settings().SetTheme(theme.DarkTheme())
+	return fyne.CurrentApp()
+})
+
+func NewMainWindow() (fyne.Window, error) {
+	windowSize := fyne.NewSize(770.0, 650.0)
+
+	//create window
+	currentApp()
 	window := newWindow(windowTitle, windowSize)
 
+	//the player can't go without the audio device
+	controller, err := newController()
+	if err != nil {
+		return nil, err
+	}
+
 	//create system tray
 	if desktop, ok := fyne.CurrentApp().(desktop.App); ok {
 		desktop.SetSystemTrayMenu(fyne.NewMenu("", fyne.NewMenuItem("Show", window.Show)))
@@ -30,7 +61,11 @@ func NewMainWindow() fyne.Window {
 	//create item tabs
 	albumTab := newAlbumTab()
 	musicTab := newMusicTab()
-	tabs := container.NewAppTabs(albumTab, musicTab)
+	stationTab := newStationTab()
+	trashTab := newTrashTab()
+	downloadTab := newDownloadTab()
+	settingsTab := newSettingsTab()
+	tabs := container.NewAppTabs(albumTab, musicTab, stationTab, downloadTab, trashTab, settingsTab)
 	tabs.SetTabLocation(container.TabLocationLeading)
 	tabs.DisableItem(musicTab)
 	client.GetAlbumData().Attach(pattern.MakeCallback(func(*resource.Album) {
@@ -38,8 +73,40 @@ func NewMainWindow() fyne.Window {
 		tabs.Select(musicTab)
 	}))
 
-	window.SetContent(container.NewBorder(nil, newController(), nil, nil, tabs))
-	return window
+	//dispatch the dropped files to the selected tab
+	window.SetOnDropped(func(pos fyne.Position, uris []fyne.URI) {
+		if onDropped, ok := dropHandlers[tabs.Selected()]; ok {
+			onDropped(pos, uris)
+		}
+	})
+
+	//undo and redo the library changes
+	window.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyZ, Modifier: fyne.KeyModifierShortcutDefault}, func(fyne.Shortcut) {
+		showErrorIfAny(client.Undo())
+	})
+	window.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyZ, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift}, func(fyne.Shortcut) {
+		showErrorIfAny(client.Redo())
+	})
+
+	window.SetContent(container.NewBorder(nil, controller, nil, nil, tabs))
+	return window, nil
+}
+
+// tell the error that stops the player from starting, the app quits once it is dismissed
+func ShowFatalError(err error) {
+	window := currentApp().NewWindow(windowTitle)
+	window.SetIcon(resource.WindowIcon())
+	window.Resize(fyne.NewSize(480.0, 240.0))
+	errorDialog := dialog.NewError(explainError(err), window)
+	errorDialog.SetOnClosed(currentApp().Quit)
+	window.SetOnClosed(currentApp().Quit)
+	errorDialog.Show()
+	window.ShowAndRun()
+}
+
+// tell the error that only costs a feature, the player starts anyway
+func ShowStartupError(err error) {
+	showErrorIfAny(err)
 }
 
 func newWindow(title string, size fyne.Size) fyne.Window {
@@ -58,6 +125,56 @@ func getWindow() fyne.Window {
 
 func showErrorIfAny(err error) {
 	if err != nil {
-		dialog.ShowError(err, getWindow())
+		dialog.ShowError(explainError(err), getWindow())
+	}
+}
+
+// put the known errors in plain words, the original error follows for the details
+func explainError(err error) error {
+	hint := ""
+	statusErr := &httpclient.StatusError{}
+	switch {
+	case errors.Is(err, resource.ErrAlbumNotFound):
+		hint = "The album is gone, it may have been deleted or renamed in the meantime."
+	case errors.Is(err, resource.ErrTrackNotFound):
+		hint = "The music is no longer in the album."
+	case errors.Is(err, resource.ErrStationNotFound):
+		hint = "The station is gone, it may have been deleted or renamed in the meantime."
+	case errors.Is(err, resource.ErrTrashNotFound):
+		hint = "The trash has already been restored or purged."
+	case errors.Is(err, resource.ErrDownloadNotFound):
+		hint = "The download has already been removed from the queue."
+	case errors.Is(err, resource.ErrDuplicateTitle):
+		hint = "The title is already taken, please pick another one."
+	case errors.Is(err, resource.ErrDuplicateFeed):
+		hint = "You are already subscribed to this podcast."
+	case errors.Is(err, network.ErrProviderNotFound):
+		hint = "The provider is no longer available, please pick another one."
+	case errors.Is(err, scraper.ErrNotFeed):
+		hint = "The link is not a podcast feed, please use the RSS link of the podcast."
+	case errors.Is(err, downloader.ErrBinaryNotFound):
+		hint = "yt-dlp and ffmpeg need to be installed to download from this provider."
+	case errors.Is(err, audio.ErrFFmpegNotFound):
+		hint = "The provider delivers the audio in a format the player can't play, ffmpeg needs to be installed to convert it."
+	case errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound:
+		hint = "The page is not found, the link may be broken."
+	case errors.As(err, &statusErr) && (statusErr.StatusCode == http.StatusForbidden || statusErr.StatusCode == http.StatusTooManyRequests):
+		hint = "The site is turning down the requests, please try again later."
+	case errors.Is(err, context.DeadlineExceeded):
+		hint = "The site took too long to answer, please check the connection or try again later."
+	default:
+		return err
+	}
+	return fmt.Errorf("%v\n\n%w", hint, err)
+}
+
+// convert the local file uris to paths
+func getLocalPaths(uris []fyne.URI) []string {
+	paths := []string{}
+	for _, uri := range uris {
+		if uri.Scheme() == "file" {
+			paths = append(paths, uri.Path())
+		}
 	}
+	return paths
 }
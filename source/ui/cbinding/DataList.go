@@ -8,6 +8,7 @@ import (
 
 type dataList[T any] struct {
 	pattern.SubjectBase[[]T]
+	source []T
 	data   container.Slice[T]
 	filter func(T) bool
 	sorter func(T, T) bool
@@ -28,11 +29,13 @@ func (d *dataList[T]) SetSorter(sorter func(T, T) bool) {
 }
 
 func (d *dataList[T]) Notify(data []T) {
-	d.data = data
+	d.source = data
 	d.updateBinding()
 }
 
+// sort a copy, the source order is the custom order and must not be touched
 func (d *dataList[T]) updateBinding() {
+	d.data = slices.Clone(d.source)
 	slices.SortStableFunc(d.data, d.sorter)
 	d.NotifyAll(d.data.Filter(d.filter))
 }
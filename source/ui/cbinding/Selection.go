@@ -0,0 +1,86 @@
+package cbinding
+
+import "slices"
+
+/*
+Keep track of the selected items of a displayed list.
+*/
+type Selection[T any] struct {
+	items    []T
+	selected map[string]bool
+	anchor   string
+	key      func(T) string
+}
+
+func MakeSelection[T any](key func(T) string) Selection[T] {
+	return Selection[T]{selected: map[string]bool{}, key: key}
+}
+
+// observe the displayed list, so that the range and select all work in the displayed order
+func (s *Selection[T]) Notify(items []T) {
+	s.items = items
+}
+
+func (s *Selection[T]) Contains(item T) bool {
+	return s.selected[s.key(item)]
+}
+
+func (s *Selection[T]) Empty() bool {
+	return len(s.selected) == 0
+}
+
+func (s *Selection[T]) Select(item T) {
+	s.Clear()
+	s.Toggle(item)
+}
+
+func (s *Selection[T]) Toggle(item T) {
+	key := s.key(item)
+	if s.selected[key] {
+		delete(s.selected, key)
+	} else {
+		s.selected[key] = true
+	}
+	s.anchor = key
+}
+
+// select everything between the last toggled item and the given item
+func (s *Selection[T]) SelectRange(item T) {
+	begin := slices.IndexFunc(s.items, func(t T) bool { return s.key(t) == s.anchor })
+	end := slices.IndexFunc(s.items, func(t T) bool { return s.key(t) == s.key(item) })
+	if end == -1 {
+		return
+	}
+	if begin == -1 {
+		begin = end
+	}
+	if begin > end {
+		begin, end = end, begin
+	}
+
+	for _, t := range s.items[begin : end+1] {
+		s.selected[s.key(t)] = true
+	}
+}
+
+func (s *Selection[T]) SelectAll() {
+	for _, t := range s.items {
+		s.selected[s.key(t)] = true
+	}
+}
+
+func (s *Selection[T]) Clear() {
+	s.selected = map[string]bool{}
+	s.anchor = ""
+}
+
+// return the selected items in the displayed order
+func (s *Selection[T]) Items() []T {
+	items := []T{}
+	for _, t := range s.items {
+		if s.Contains(t) {
+			items = append(items, t)
+		}
+	}
+	return items
+}
@@ -0,0 +1,15 @@
+package cbinding
+
+import "meowyplayer.com/source/resource"
+
+type StationDataList struct {
+	dataList[resource.Station]
+}
+
+func MakeStationDataList() StationDataList {
+	return StationDataList{makeDataList[resource.Station]()}
+}
+
+func (s *StationDataList) Notify(collection *resource.Collection) {
+	s.dataList.Notify(collection.Stations)
+}
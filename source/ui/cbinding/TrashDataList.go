@@ -0,0 +1,15 @@
+package cbinding
+
+import "meowyplayer.com/source/resource"
+
+type TrashDataList struct {
+	dataList[resource.Trash]
+}
+
+func MakeTrashDataList() TrashDataList {
+	return TrashDataList{makeDataList[resource.Trash]()}
+}
+
+func (t *TrashDataList) Notify(collection *resource.Collection) {
+	t.dataList.Notify(collection.Trash)
+}
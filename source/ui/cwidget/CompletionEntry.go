@@ -0,0 +1,86 @@
+package cwidget
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const maxShownSuggestions = 6
+
+// an entry listing the suggestions for its text below it, picking one submits it
+// the OnChanged callback is taken by the suggestions
+type CompletionEntry struct {
+	widget.Entry
+	suggest     func(text string) []string
+	suggestions []string
+	list        *widget.List
+	popUp       *widget.PopUp
+	picking     bool
+}
+
+func NewCompletionEntry(suggest func(text string) []string) *CompletionEntry {
+	entry := &CompletionEntry{suggest: suggest}
+	entry.OnChanged = entry.showSuggestions
+	entry.ExtendBaseWidget(entry)
+	return entry
+}
+
+func (e *CompletionEntry) showSuggestions(text string) {
+	if e.picking {
+		return
+	}
+	e.suggestions = e.suggest(text)
+	if len(e.suggestions) == 0 {
+		e.hideSuggestions()
+		return
+	}
+
+	//the pop up doesn't take the focus, so the typing goes on in the entry
+	if e.popUp == nil {
+		e.list = widget.NewList(
+			func() int { return len(e.suggestions) },
+			func() fyne.CanvasObject { return widget.NewLabel("") },
+			func(id widget.ListItemID, object fyne.CanvasObject) {
+				object.(*widget.Label).SetText(e.suggestions[id])
+			},
+		)
+		e.list.OnSelected = e.pick
+		e.popUp = widget.NewPopUp(e.list, fyne.CurrentApp().Driver().CanvasForObject(e))
+	}
+	e.list.Refresh()
+
+	itemHeight := widget.NewLabel("").MinSize().Height + theme.SeparatorThicknessSize()
+	height := float32(min(len(e.suggestions), maxShownSuggestions)) * itemHeight
+	position := fyne.CurrentApp().Driver().AbsolutePositionForObject(e).AddXY(0, e.Size().Height)
+	e.popUp.ShowAtPosition(position)
+	e.popUp.Resize(fyne.NewSize(e.Size().Width, height))
+}
+
+func (e *CompletionEntry) hideSuggestions() {
+	if e.popUp != nil {
+		e.popUp.Hide()
+	}
+}
+
+func (e *CompletionEntry) pick(id widget.ListItemID) {
+	suggestion := e.suggestions[id]
+	e.list.UnselectAll()
+	e.hideSuggestions()
+
+	e.picking = true
+	e.SetText(suggestion)
+	e.picking = false
+	if e.OnSubmitted != nil {
+		e.OnSubmitted(suggestion)
+	}
+}
+
+// the suggestions are dismissed by escape or by submitting the text
+func (e *CompletionEntry) TypedKey(key *fyne.KeyEvent) {
+	switch key.Name {
+	case fyne.KeyEscape, fyne.KeyReturn, fyne.KeyEnter:
+		e.hideSuggestions()
+	}
+	e.Entry.TypedKey(key)
+}
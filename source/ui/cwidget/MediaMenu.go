@@ -74,6 +74,17 @@ func (c *MediaMenu) SetMusic(music *resource.Music) {
 	c.progressSlider.SetValue(0.0)
 }
 
+// a station is live, the title shows what it is playing now
+func (c *MediaMenu) SetStation(station *resource.Station, nowPlaying string) {
+	if nowPlaying == "" {
+		c.title.SetText(station.Title)
+	} else {
+		c.title.SetText(fmt.Sprintf("%v | %v", station.Title, nowPlaying))
+	}
+	c.progressSlider.SetValue(0.0)
+	c.durationLabel.SetText("LIVE")
+}
+
 func (c *MediaMenu) UpdateProgress(length time.Duration, percent float64) {
 	const kConversionFactor = 60
 	length = time.Duration(float64(length) * percent)
@@ -15,20 +15,59 @@ type MusicView struct {
 	tappableBase
 	title     *widget.Label
 	highlight *canvas.Rectangle
+	selection *canvas.Rectangle
+	modifier  fyne.KeyModifier
+	dragPos   fyne.Position
+	OnDropped func(fyne.Position)
 }
 
 func NewMusicView(music *resource.Music) *MusicView {
 	view := &MusicView{
 		title:     widget.NewLabel(music.Description()),
 		highlight: canvas.NewRectangle(theme.HoverColor()),
+		selection: canvas.NewRectangle(theme.SelectionColor()),
 	}
 	view.highlight.Hide()
+	view.selection.Hide()
 	view.ExtendBaseWidget(view)
 	return view
 }
 
 func (m *MusicView) CreateRenderer() fyne.WidgetRenderer {
-	return widget.NewSimpleRenderer(container.NewMax(m.title, m.highlight))
+	return widget.NewSimpleRenderer(container.NewMax(m.selection, m.title, m.highlight))
+}
+
+func (m *MusicView) SetSelected(selected bool) {
+	if selected {
+		m.selection.Show()
+	} else {
+		m.selection.Hide()
+	}
+	m.Refresh()
+}
+
+// return the key modifier held during the last tap
+func (m *MusicView) Modifier() fyne.KeyModifier {
+	return m.modifier
+}
+
+func (m *MusicView) Dragged(event *fyne.DragEvent) {
+	m.dragPos = event.AbsolutePosition
+}
+
+// report where the view is dropped in absolute position
+func (m *MusicView) DragEnd() {
+	if m.OnDropped != nil {
+		m.OnDropped(m.dragPos)
+	}
+}
+
+func (m *MusicView) MouseDown(event *desktop.MouseEvent) {
+	m.modifier = event.Modifier
+}
+
+func (m *MusicView) MouseUp(*desktop.MouseEvent) {
+	//satisfy Mouseable interface
 }
 
 func (m *MusicView) MouseIn(*desktop.MouseEvent) {
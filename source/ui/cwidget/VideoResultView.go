@@ -1,7 +1,10 @@
 package cwidget
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sync"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
@@ -19,38 +22,90 @@ type VideoResultView struct {
 	title        *widget.Label
 	channelTitle *widget.Label
 	stats        *widget.Label
+	preview      *widget.Label
+	badge        *widget.Label
 	download     *widget.Button
 	highlight    *canvas.Rectangle
+	cancel       context.CancelFunc //set while downloading, cleared by the download goroutine
+	cancelLock   sync.Mutex
 }
 
-func NewVideoResultView(result *fileformat.VideoResult, size fyne.Size, onDownload func(videoResult *fileformat.VideoResult)) *VideoResultView {
+func NewVideoResultView(result *fileformat.VideoResult, size fyne.Size, onDownload func(ctx context.Context, videoResult *fileformat.VideoResult) error) *VideoResultView {
 	const kConversionFactor = 60
 	mins := int(result.Length.Minutes()) % kConversionFactor
 	secs := int(result.Length.Seconds()) % kConversionFactor
 
 	view := &VideoResultView{
-		thumbnail:    canvas.NewImageFromResource(result.Thumbnail),
+		thumbnail:    canvas.NewImageFromResource(theme.FileImageIcon()),
 		title:        widget.NewLabelWithStyle(fmt.Sprintf("[%02v:%02v] %v", mins, secs, result.Title), fyne.TextAlignLeading, fyne.TextStyle{Bold: true, Symbol: true}),
 		channelTitle: widget.NewLabel(result.ChannelTitle),
 		stats:        widget.NewLabel(result.Stats),
+		preview:      widget.NewLabel(""),
+		badge:        widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Italic: true}),
 		download:     NewButtonWithIcon("", theme.DownloadIcon(), nil),
 		highlight:    canvas.NewRectangle(theme.HoverColor()),
 	}
 	view.thumbnail.SetMinSize(size)
+	view.thumbnail.FillMode = canvas.ImageFillContain
 	view.title.Wrapping = fyne.TextWrapWord
+	view.preview.Importance = widget.LowImportance
+	view.preview.Hide()
+	view.badge.Importance = widget.SuccessImportance
+	view.badge.Hide()
 	view.highlight.Hide()
-	view.download.OnTapped = func() {
-		view.download.Disable()
-		go func() {
-			view.download.SetIcon(theme.MoreHorizontalIcon())
-			onDownload(result)
-			view.download.SetIcon(theme.ConfirmIcon())
-		}()
-	}
+	view.download.OnTapped = func() { view.startDownload(result, onDownload) }
 	view.ExtendBaseWidget(view)
 	return view
 }
 
+// replace the placeholder once the thumbnail is loaded
+func (v *VideoResultView) SetThumbnail(thumbnail fyne.Resource) {
+	v.thumbnail.Resource = thumbnail
+	v.thumbnail.Refresh()
+}
+
+// show what the result would be saved as
+func (v *VideoResultView) SetPreview(preview string) {
+	v.preview.SetText(preview)
+	v.preview.Show()
+}
+
+// mark the result, e.g. when it is already in the library
+func (v *VideoResultView) SetBadge(badge string) {
+	v.badge.SetText(badge)
+	v.badge.Show()
+}
+
+// tapping the button again during the download aborts it
+func (v *VideoResultView) startDownload(result *fileformat.VideoResult, onDownload func(context.Context, *fileformat.VideoResult) error) {
+	v.cancelLock.Lock()
+	defer v.cancelLock.Unlock()
+	if v.cancel != nil {
+		v.cancel()
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	v.cancel = cancel
+	v.download.SetIcon(theme.CancelIcon())
+	go func() {
+		err := onDownload(ctx, result)
+		cancel()
+		v.cancelLock.Lock()
+		v.cancel = nil
+		v.cancelLock.Unlock()
+		switch {
+		case err == nil:
+			v.download.SetIcon(theme.ConfirmIcon())
+			v.download.Disable()
+		case errors.Is(err, context.Canceled):
+			v.download.SetIcon(theme.DownloadIcon())
+		default:
+			v.download.SetIcon(theme.ErrorIcon())
+		}
+	}()
+}
+
 func (v *VideoResultView) CreateRenderer() fyne.WidgetRenderer {
 	return widget.NewSimpleRenderer(container.NewMax(
 		v.highlight,
@@ -59,7 +114,7 @@ func (v *VideoResultView) CreateRenderer() fyne.WidgetRenderer {
 			nil,
 			v.thumbnail,
 			v.download,
-			container.NewVBox(v.title, v.channelTitle, v.stats),
+			container.NewVBox(v.title, v.channelTitle, v.stats, v.preview, v.badge),
 		),
 	))
 }
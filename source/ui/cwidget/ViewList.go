@@ -11,11 +11,18 @@ type ViewList[T any] struct {
 	widget.BaseWidget
 	display  *fyne.Container
 	scroll   *container.Scroll
+	data     []T
 	makeView func(T) fyne.CanvasObject
+
+	onEndReached func()
 }
 
+// how close to the end the list is scrolled before more data is asked for
+const endReachedThreshold = 64.0
+
 func NewViewList[T any](dataList pattern.Subject[[]T], display *fyne.Container, makeView func(T) fyne.CanvasObject) *ViewList[T] {
 	viewList := &ViewList[T]{display: display, scroll: container.NewScroll(display), makeView: makeView}
+	viewList.scroll.OnScrolled = viewList.onScrolled
 	dataList.Attach(viewList)
 	viewList.ExtendBaseWidget(viewList)
 	return viewList
@@ -26,6 +33,17 @@ func (v *ViewList[T]) CreateRenderer() fyne.WidgetRenderer {
 }
 
 func (v *ViewList[T]) Notify(data []T) {
+	//the grown data of a paged list only appends the new views and keeps the scroll position
+	if v.onEndReached != nil && 0 < len(v.data) && len(v.data) < len(data) {
+		for _, view := range v.makeViews(data[len(v.data):]) {
+			v.display.Add(view)
+		}
+		v.data = data
+		v.Refresh()
+		return
+	}
+
+	v.data = data
 	v.display.RemoveAll()
 	views := v.makeViews(data)
 	for _, view := range views {
@@ -42,3 +60,41 @@ func (v *ViewList[T]) makeViews(data []T) []fyne.CanvasObject {
 	}
 	return views
 }
+
+// call back whenever the list is scrolled to the end, so that the next page can be loaded
+// the paged data must be set to empty before a new list is set
+func (v *ViewList[T]) SetOnEndReached(onEndReached func()) {
+	v.onEndReached = onEndReached
+}
+
+func (v *ViewList[T]) onScrolled(offset fyne.Position) {
+	if v.onEndReached != nil && offset.Y+v.scroll.Size().Height >= v.display.MinSize().Height-endReachedThreshold {
+		v.onEndReached()
+	}
+}
+
+// return the data whose view is under the absolute position
+func (v *ViewList[T]) ItemAt(pos fyne.Position) (T, bool) {
+	if containsPosition(v.scroll, pos) {
+		for i, view := range v.display.Objects {
+			if containsPosition(view, pos) {
+				return v.data[i], true
+			}
+		}
+	}
+	var empty T
+	return empty, false
+}
+
+func containsPosition(object fyne.CanvasObject, pos fyne.Position) bool {
+	topLeft := fyne.CurrentApp().Driver().AbsolutePositionForObject(object)
+	bottomRight := topLeft.Add(object.Size())
+	return object.Visible() && topLeft.X <= pos.X && pos.X < bottomRight.X && topLeft.Y <= pos.Y && pos.Y < bottomRight.Y
+}
+
+// apply the update to every view along with its data
+func (v *ViewList[T]) UpdateViews(update func(T, fyne.CanvasObject)) {
+	for i, view := range v.display.Objects {
+		update(v.data[i], view)
+	}
+}
@@ -0,0 +1,74 @@
+package audio
+
+import (
+	"io"
+	"os"
+
+	"github.com/hajimehoshi/go-mp3"
+)
+
+const readBufferSize = 64 * 1024
+
+/*
+Decode the mp3 file as it plays, instead of reading all of it into the memory.
+Only the positions of the frames are kept, so that the file can still be seeked.
+*/
+type MP3File struct {
+	*mp3.Decoder
+	file *os.File
+}
+
+func OpenMP3(path string) (*MP3File, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	decoder, err := mp3.NewDecoder(newBufferedReadSeeker(file, readBufferSize))
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &MP3File{Decoder: decoder, file: file}, nil
+}
+
+func (m *MP3File) Close() error {
+	return m.file.Close()
+}
+
+// a read buffer in front of the file, so that the small reads of the decoder don't each cost a system call
+type bufferedReadSeeker struct {
+	rs     io.ReadSeeker
+	buffer []byte
+	start  int //the unread part of the buffer is buffer[start:end]
+	end    int
+}
+
+func newBufferedReadSeeker(rs io.ReadSeeker, size int) *bufferedReadSeeker {
+	return &bufferedReadSeeker{rs: rs, buffer: make([]byte, size)}
+}
+
+func (b *bufferedReadSeeker) Read(p []byte) (int, error) {
+	if b.start == b.end {
+		//the large reads skip the buffer
+		if len(p) >= len(b.buffer) {
+			return b.rs.Read(p)
+		}
+		n, err := b.rs.Read(b.buffer)
+		b.start, b.end = 0, n
+		if n == 0 {
+			return 0, err
+		}
+	}
+	n := copy(p, b.buffer[b.start:b.end])
+	b.start += n
+	return n, nil
+}
+
+// the buffer is dropped, the underlying reader is ahead of the reading position by the unread part
+func (b *bufferedReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	if whence == io.SeekCurrent {
+		offset -= int64(b.end - b.start)
+	}
+	b.start, b.end = 0, 0
+	return b.rs.Seek(offset, whence)
+}
@@ -0,0 +1,144 @@
+package audio_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/hajimehoshi/go-mp3"
+	"meowyplayer.com/utility/audio"
+)
+
+const (
+	frameSize    = 417  //a 128 kbps frame at 44.1 kHz without padding
+	frameSamples = 1152 //per channel
+	framesPerMin = 44100 * 60 / frameSamples
+)
+
+// write frames of silence, each one is a header followed by the zeroed side information and data
+func writeSilentMP3(tb testing.TB, frames int) string {
+	frame := make([]byte, frameSize)
+	copy(frame, []byte{0xFF, 0xFB, 0x90, 0x00})
+	path := filepath.Join(tb.TempDir(), "silence.mp3")
+	file, err := os.Create(path)
+	if err != nil {
+		tb.Fatalf("%v\n", err)
+	}
+	defer file.Close()
+	for i := 0; i < frames; i++ {
+		if _, err := file.Write(frame); err != nil {
+			tb.Fatalf("%v\n", err)
+		}
+	}
+	return path
+}
+
+func TestOpenMP3(t *testing.T) {
+	const frames = 100
+	path := writeSilentMP3(t, frames)
+	file, err := audio.OpenMP3(path)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	defer file.Close()
+
+	//16 bits stereo
+	if file.Length() != frames*frameSamples*4 {
+		t.Errorf("expected %v bytes, got %v\n", frames*frameSamples*4, file.Length())
+	}
+
+	//the file decodes to the same audio as the data in the memory
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	decoder, err := mp3.NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	expected, err := io.ReadAll(decoder)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	half := file.Length() / 2
+	if _, err := file.Seek(half, io.SeekStart); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	decoded, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if !bytes.Equal(decoded, expected[half:]) {
+		t.Errorf("expected %v bytes after seeking to the middle, got %v\n", len(expected[half:]), len(decoded))
+	}
+}
+
+func TestOpenMP3Missing(t *testing.T) {
+	if _, err := audio.OpenMP3(filepath.Join(t.TempDir(), "missing.mp3")); !os.IsNotExist(err) {
+		t.Errorf("expected the missing file to be reported, got %v\n", err)
+	}
+}
+
+// report the heap still in use while the music plays, which is what a track change costs
+func benchmarkRetainedHeap(b *testing.B, minutes int, open func(path string) (io.Reader, func())) {
+	path := writeSilentMP3(b, minutes*framesPerMin)
+	buffer := make([]byte, 44100*4) //a second of audio
+
+	retained := uint64(0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		before := runtime.MemStats{}
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+
+		reader, close := open(path)
+		if _, err := io.ReadFull(reader, buffer); err != nil {
+			b.Fatalf("%v\n", err)
+		}
+
+		after := runtime.MemStats{}
+		runtime.GC()
+		runtime.ReadMemStats(&after)
+		retained += after.HeapAlloc - min(after.HeapAlloc, before.HeapAlloc)
+		runtime.KeepAlive(reader)
+		close()
+	}
+	b.ReportMetric(float64(retained)/float64(b.N)/(1<<20), "retained-MB/op")
+}
+
+func BenchmarkDecodeFromMemory(b *testing.B) {
+	for _, minutes := range []int{10, 120} {
+		b.Run(fmt.Sprintf("%vmin", minutes), func(b *testing.B) {
+			benchmarkRetainedHeap(b, minutes, func(path string) (io.Reader, func()) {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					b.Fatalf("%v\n", err)
+				}
+				decoder, err := mp3.NewDecoder(bytes.NewReader(data))
+				if err != nil {
+					b.Fatalf("%v\n", err)
+				}
+				return decoder, func() {}
+			})
+		})
+	}
+}
+
+func BenchmarkDecodeFromFile(b *testing.B) {
+	for _, minutes := range []int{10, 120} {
+		b.Run(fmt.Sprintf("%vmin", minutes), func(b *testing.B) {
+			benchmarkRetainedHeap(b, minutes, func(path string) (io.Reader, func()) {
+				file, err := audio.OpenMP3(path)
+				if err != nil {
+					b.Fatalf("%v\n", err)
+				}
+				return file, func() { file.Close() }
+			})
+		})
+	}
+}
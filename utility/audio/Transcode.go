@@ -0,0 +1,37 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+var ErrFFmpegNotFound = errors.New("ffmpeg is not installed or not in PATH")
+
+// the player only decodes mp3, the other formats are converted by the locally installed ffmpeg
+// the input is read from a file, since an mp4 container may keep its index at the end
+func TranscodeToMP3(ctx context.Context, input, output string, bitrate int) error {
+	ffmpeg, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return ErrFFmpegNotFound
+	}
+
+	stderr := bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, ffmpeg, "-y", "-loglevel", "error", "-i", input, "-vn", "-codec:a", "libmp3lame", "-b:a", fmt.Sprintf("%vk", bitrate), "-f", "mp3", output)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return fmt.Errorf("ffmpeg failed: %v: %w", message, err)
+		}
+		return fmt.Errorf("ffmpeg failed: %w", err)
+	}
+	return nil
+}
+
+// tell the mp4 container (aac) from the mp3 by its file type box
+func IsMP4(header []byte) bool {
+	return len(header) >= 8 && string(header[4:8]) == "ftyp"
+}
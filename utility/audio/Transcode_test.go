@@ -0,0 +1,91 @@
+package audio_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"meowyplayer.com/utility/audio"
+)
+
+// the fake ffmpeg records its arguments and copies the input to the output, which is the last argument
+const fakeFFmpeg = `#!/bin/sh
+echo "$@" > "${0%/*}/args"
+while [ $# -gt 1 ]; do
+	if [ "$1" = "-i" ]; then input="$2"; fi
+	shift
+done
+if [ "$input" = "${input%.m4a}" ]; then
+	echo "invalid data found when processing input" >&2
+	exit 1
+fi
+while IFS= read -r line; do echo "$line"; done < "$input" > "$1"
+`
+
+func installFakeFFmpeg(t *testing.T, script string) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake ffmpeg is a shell script")
+	}
+	folder := t.TempDir()
+	if script != "" {
+		if err := os.WriteFile(filepath.Join(folder, "ffmpeg"), []byte(script), 0755); err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}
+	t.Setenv("PATH", folder)
+	return folder
+}
+
+func TestTranscodeToMP3(t *testing.T) {
+	folder := installFakeFFmpeg(t, fakeFFmpeg)
+	input, output := filepath.Join(t.TempDir(), "music.m4a"), filepath.Join(t.TempDir(), "music.mp3")
+	if err := os.WriteFile(input, []byte("aac audio\n"), 0666); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if err := audio.TranscodeToMP3(context.Background(), input, output, 192); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if data, err := os.ReadFile(output); err != nil || string(data) != "aac audio\n" {
+		t.Errorf("unexpected output: %q, %v\n", data, err)
+	}
+	args, err := os.ReadFile(filepath.Join(folder, "args"))
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	for _, arg := range []string{"-codec:a libmp3lame", "-b:a 192k", "-f mp3"} {
+		if !strings.Contains(string(args), arg) {
+			t.Errorf("missing %q in the arguments: %s\n", arg, args)
+		}
+	}
+}
+
+func TestTranscodeFailure(t *testing.T) {
+	installFakeFFmpeg(t, fakeFFmpeg)
+	input := filepath.Join(t.TempDir(), "music.wav")
+	err := audio.TranscodeToMP3(context.Background(), input, filepath.Join(t.TempDir(), "music.mp3"), 192)
+	if err == nil || !strings.Contains(err.Error(), "invalid data") {
+		t.Errorf("expected the error of ffmpeg to be reported, got %v\n", err)
+	}
+}
+
+func TestTranscodeWithoutFFmpeg(t *testing.T) {
+	installFakeFFmpeg(t, "")
+	err := audio.TranscodeToMP3(context.Background(), "music.m4a", "music.mp3", 192)
+	if !errors.Is(err, audio.ErrFFmpegNotFound) {
+		t.Errorf("expected %v, got %v\n", audio.ErrFFmpegNotFound, err)
+	}
+}
+
+func TestIsMP4(t *testing.T) {
+	if !audio.IsMP4([]byte("\x00\x00\x00\x20ftypM4A ")) {
+		t.Errorf("expected the mp4 header to be detected\n")
+	}
+	if audio.IsMP4([]byte{0xFF, 0xFB, 0x90, 0x00, 0, 0, 0, 0}) || audio.IsMP4([]byte("ftyp")) {
+		t.Errorf("expected the mp3 frame and the short data to be rejected\n")
+	}
+}
@@ -0,0 +1,126 @@
+package cleaner
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+type Case uint8
+
+const (
+	KeepCase  Case = iota
+	TitleCase      //capitalize the words, the shouted ones are lowered first
+	LowerCase
+	UpperCase
+)
+
+var Cases = []Case{KeepCase, TitleCase, LowerCase, UpperCase}
+
+func (c Case) String() string {
+	return [...]string{"Keep", "Title Case", "lower case", "UPPER CASE"}[c]
+}
+
+// the rules are applied in the order of the fields
+type Rules struct {
+	Removals    []string `json:"removals"`    //regular expressions removed from the title
+	SplitArtist bool     `json:"splitArtist"` //read "Artist - Title" into the artist and the title
+	Case        Case     `json:"case"`
+}
+
+func DefaultRules() Rules {
+	return Rules{
+		Removals: []string{
+			`(?i)[(\[]\s*(official\s*)?(music\s*|lyrics?\s*)?(video|audio|lyrics?|visualizer|mv|pv)\s*[)\]]`,
+			`(?i)[(\[]\s*(hd|hq|4k|8k|1080p|720p)\s*[)\]]`,
+			`【[^】]*】`,
+		},
+		SplitArtist: true,
+		Case:        KeepCase,
+	}
+}
+
+type Result struct {
+	Artist string //empty if the title names no artist
+	Title  string
+}
+
+type Cleaner struct {
+	removals    []*regexp.Regexp
+	splitArtist bool
+	titleCase   Case
+}
+
+// an invalid regular expression is reported along with its position in the rules
+func New(rules *Rules) (*Cleaner, error) {
+	cleaner := &Cleaner{splitArtist: rules.SplitArtist, titleCase: rules.Case}
+	for i, removal := range rules.Removals {
+		regex, err := regexp.Compile(removal)
+		if err != nil {
+			return nil, fmt.Errorf("invalid removal rule %v: %w", i+1, err)
+		}
+		cleaner.removals = append(cleaner.removals, regex)
+	}
+	return cleaner, nil
+}
+
+// the title is left as it is if the rules would remove all of it
+func (c *Cleaner) Clean(title string) Result {
+	cleaned := title
+	for _, regex := range c.removals {
+		cleaned = regex.ReplaceAllString(cleaned, " ")
+	}
+	cleaned = tidy(cleaned)
+	if cleaned == "" {
+		return Result{Title: title}
+	}
+
+	result := Result{Title: cleaned}
+	if c.splitArtist {
+		for _, separator := range []string{" - ", " – ", " — "} {
+			if artist, rest, found := strings.Cut(cleaned, separator); found && tidy(artist) != "" && tidy(rest) != "" {
+				result = Result{Artist: tidy(artist), Title: tidy(rest)}
+				break
+			}
+		}
+	}
+	result.Artist = changeCase(result.Artist, c.titleCase)
+	result.Title = changeCase(result.Title, c.titleCase)
+	return result
+}
+
+// collapse the spaces and drop the separators left dangling by the removals
+func tidy(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	return strings.TrimFunc(text, func(r rune) bool { return unicode.IsSpace(r) || strings.ContainsRune("-–—|/~", r) })
+}
+
+func changeCase(text string, c Case) string {
+	switch c {
+	case LowerCase:
+		return strings.ToLower(text)
+	case UpperCase:
+		return strings.ToUpper(text)
+	case TitleCase:
+		words := strings.Split(text, " ")
+		for i, word := range words {
+			words[i] = capitalize(word)
+		}
+		return strings.Join(words, " ")
+	}
+	return text
+}
+
+// the mixed case words such as "iPhone" are kept, the shouted ones are lowered
+func capitalize(word string) string {
+	lower, upper := strings.ToLower(word), strings.ToUpper(word)
+	if word != lower && word != upper {
+		return word
+	}
+	runes := []rune(lower)
+	if len(runes) > 0 {
+		runes[0] = unicode.ToUpper(runes[0])
+	}
+	return string(runes)
+}
@@ -0,0 +1,68 @@
+package cleaner_test
+
+import (
+	"testing"
+
+	"meowyplayer.com/utility/cleaner"
+)
+
+func newCleaner(t *testing.T, rules cleaner.Rules) *cleaner.Cleaner {
+	c, err := cleaner.New(&rules)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	return c
+}
+
+func TestCleanDefaultRules(t *testing.T) {
+	c := newCleaner(t, cleaner.DefaultRules())
+	tests := []struct {
+		title    string
+		expected cleaner.Result
+	}{
+		{"ARTIST - Song (Official Music Video) [4K] 【MV】", cleaner.Result{Artist: "ARTIST", Title: "Song"}},
+		{"Renai Circulation「恋愛サーキュレーション」(Lyrics)", cleaner.Result{Title: "Renai Circulation「恋愛サーキュレーション」"}},
+		{"Meowy – Into The Light [HD]", cleaner.Result{Artist: "Meowy", Title: "Into The Light"}},
+		{"Mouso Express (Live at Budokan)", cleaner.Result{Title: "Mouso Express (Live at Budokan)"}},
+		{"- Mouso Express -", cleaner.Result{Title: "Mouso Express"}},
+		{"【MV】", cleaner.Result{Title: "【MV】"}},
+	}
+
+	for _, test := range tests {
+		if result := c.Clean(test.title); result != test.expected {
+			t.Errorf("%q: expected %+v, got %+v\n", test.title, test.expected, result)
+		}
+	}
+}
+
+func TestCleanCase(t *testing.T) {
+	tests := []struct {
+		c        cleaner.Case
+		expected cleaner.Result
+	}{
+		{cleaner.KeepCase, cleaner.Result{Artist: "MEOWY", Title: "renai circulation on iPhone"}},
+		{cleaner.TitleCase, cleaner.Result{Artist: "Meowy", Title: "Renai Circulation On iPhone"}},
+		{cleaner.LowerCase, cleaner.Result{Artist: "meowy", Title: "renai circulation on iphone"}},
+		{cleaner.UpperCase, cleaner.Result{Artist: "MEOWY", Title: "RENAI CIRCULATION ON IPHONE"}},
+	}
+
+	for _, test := range tests {
+		c := newCleaner(t, cleaner.Rules{SplitArtist: true, Case: test.c})
+		if result := c.Clean("MEOWY - renai circulation on iPhone"); result != test.expected {
+			t.Errorf("%v: expected %+v, got %+v\n", test.c, test.expected, result)
+		}
+	}
+}
+
+func TestCleanWithoutSplit(t *testing.T) {
+	c := newCleaner(t, cleaner.Rules{Removals: []string{`\s*#\w+`}})
+	if result := c.Clean("Meowy - Renai Circulation #shorts #cover"); result != (cleaner.Result{Title: "Meowy - Renai Circulation"}) {
+		t.Errorf("unexpected result: %+v\n", result)
+	}
+}
+
+func TestNewInvalidRule(t *testing.T) {
+	if _, err := cleaner.New(&cleaner.Rules{Removals: []string{`\(official`, `(unclosed`}}); err == nil {
+		t.Errorf("expected the invalid rule to be rejected\n")
+	}
+}
@@ -1,6 +1,9 @@
 package container
 
-import "meowyplayer.com/utility/assert"
+import (
+	"fmt"
+	"slices"
+)
 
 type Slice[T any] []T
 
@@ -28,10 +31,20 @@ func (v *Slice[T]) Clear() {
 	*v = []T{}
 }
 
+// remove the element while keeping the order of the rest
 func (v *Slice[T]) Remove(index int) {
-	assert.Ensure(func() bool { return 0 <= index && index < v.Size() })
-	(*v)[index] = *v.Back()
-	v.PopBack()
+	*v = slices.Delete(*v, index, index+1)
+}
+
+// move the element to the index, shifting the elements in between
+// panic before touching the slice if either index is out of range
+func (v *Slice[T]) Move(from, to int) {
+	if from < 0 || v.Size() <= from || to < 0 || v.Size() <= to {
+		panic(fmt.Sprintf("move from %v to %v out of range [0, %v)", from, to, v.Size()))
+	}
+	data := (*v)[from]
+	v.Remove(from)
+	*v = slices.Insert(*v, to, data)
 }
 
 func (v *Slice[T]) Filter(filter func(T) bool) Slice[T] {
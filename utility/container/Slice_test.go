@@ -0,0 +1,45 @@
+package container_test
+
+import (
+	"slices"
+	"testing"
+
+	"meowyplayer.com/utility/container"
+)
+
+func TestRemoveKeepsOrder(t *testing.T) {
+	data := container.Slice[int]{0, 1, 2, 3, 4}
+	data.Remove(1)
+	if !slices.Equal(data, []int{0, 2, 3, 4}) {
+		t.Fatalf("unexpected order: %v\n", data)
+	}
+}
+
+func TestMoveForward(t *testing.T) {
+	data := container.Slice[int]{0, 1, 2, 3, 4}
+	data.Move(1, 3)
+	if !slices.Equal(data, []int{0, 2, 3, 1, 4}) {
+		t.Fatalf("unexpected order: %v\n", data)
+	}
+}
+
+func TestMoveBackward(t *testing.T) {
+	data := container.Slice[int]{0, 1, 2, 3, 4}
+	data.Move(4, 0)
+	if !slices.Equal(data, []int{4, 0, 1, 2, 3}) {
+		t.Fatalf("unexpected order: %v\n", data)
+	}
+}
+
+func TestMoveOutOfRange(t *testing.T) {
+	data := container.Slice[int]{0, 1, 2}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected the move out of range to panic\n")
+		}
+		if !slices.Equal(data, []int{0, 1, 2}) {
+			t.Errorf("expected the slice to be untouched, got %v\n", data)
+		}
+	}()
+	data.Move(1, 3)
+}
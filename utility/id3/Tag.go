@@ -0,0 +1,130 @@
+package id3
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"unicode/utf16"
+)
+
+const (
+	headerSize      = 10
+	encodingLatin1  = 0x00
+	encodingUTF16   = 0x01
+	pictureFront    = 0x03
+	flagFooter      = 0x10
+	maxSyncSafeSize = 1<<28 - 1
+)
+
+/*
+The ID3v2.3 tag of an mp3 file, the empty fields are left out.
+https://id3.org/id3v2.3.0
+*/
+type Tag struct {
+	Title       string
+	Artist      string
+	URL         string //written as both a user defined link and a comment, since most players only show the comments
+	Picture     []byte
+	PictureMIME string
+}
+
+// put the tag in front of the audio, the existing ID3v2 tag is replaced
+func Write(tag *Tag, audio []byte) []byte {
+	return append(tag.Encode(), Strip(audio)...)
+}
+
+// return the audio without its leading ID3v2 tag
+func Strip(audio []byte) []byte {
+	return audio[min(tagSize(audio), len(audio)):]
+}
+
+// write the tag followed by the audio read from the reader, the existing ID3v2 tag is replaced
+// the audio is streamed, so that it is never held in the memory as a whole
+func Copy(w io.Writer, tag *Tag, audio io.Reader) error {
+	if _, err := w.Write(tag.Encode()); err != nil {
+		return err
+	}
+	header := make([]byte, headerSize)
+	n, err := io.ReadFull(audio, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return err
+	}
+	if size := tagSize(header[:n]); size > 0 {
+		if _, err := io.CopyN(io.Discard, audio, int64(size-n)); err != nil && err != io.EOF {
+			return err
+		}
+	} else if _, err := w.Write(header[:n]); err != nil {
+		return err
+	}
+	_, err = io.Copy(w, audio)
+	return err
+}
+
+// the size of the leading ID3v2 tag along with its footer, zero if there is none
+func tagSize(header []byte) int {
+	if len(header) < headerSize || string(header[:3]) != "ID3" {
+		return 0
+	}
+	size := headerSize + int(syncSafeInt(header[6:10]))
+	if header[5]&flagFooter != 0 {
+		size += headerSize
+	}
+	return size
+}
+
+func (t *Tag) Encode() []byte {
+	frames := bytes.Buffer{}
+	if t.Title != "" {
+		writeFrame(&frames, "TIT2", append([]byte{encodingUTF16}, utf16String(t.Title, false)...))
+	}
+	if t.Artist != "" {
+		writeFrame(&frames, "TPE1", append([]byte{encodingUTF16}, utf16String(t.Artist, false)...))
+	}
+	if t.URL != "" {
+		//the links are always latin-1, only the description is encoded
+		link := append([]byte{encodingUTF16}, utf16String("Source", true)...)
+		writeFrame(&frames, "WXXX", append(link, t.URL...))
+
+		comment := append([]byte{encodingUTF16}, "eng"...)
+		comment = append(comment, utf16String("", true)...)
+		writeFrame(&frames, "COMM", append(comment, utf16String(t.URL, false)...))
+	}
+	if len(t.Picture) > 0 {
+		picture := append([]byte{encodingLatin1}, t.PictureMIME...)
+		picture = append(picture, 0x00, pictureFront, 0x00)
+		writeFrame(&frames, "APIC", append(picture, t.Picture...))
+	}
+
+	header := []byte{'I', 'D', '3', 0x03, 0x00, 0x00}
+	header = append(header, syncSafeBytes(uint32(min(frames.Len(), maxSyncSafeSize)))...)
+	return append(header, frames.Bytes()...)
+}
+
+// the v2.3 frame sizes are plain big endian, unlike the tag size
+func writeFrame(buffer *bytes.Buffer, id string, content []byte) {
+	buffer.WriteString(id)
+	binary.Write(buffer, binary.BigEndian, uint32(len(content)))
+	buffer.Write([]byte{0x00, 0x00})
+	buffer.Write(content)
+}
+
+// little endian with the byte order mark, terminated if it is followed by another field
+func utf16String(text string, terminated bool) []byte {
+	data := []byte{0xFF, 0xFE}
+	for _, unit := range utf16.Encode([]rune(text)) {
+		data = binary.LittleEndian.AppendUint16(data, unit)
+	}
+	if terminated {
+		data = append(data, 0x00, 0x00)
+	}
+	return data
+}
+
+// the tag size keeps the top bit of every byte clear
+func syncSafeBytes(n uint32) []byte {
+	return []byte{byte(n >> 21 & 0x7F), byte(n >> 14 & 0x7F), byte(n >> 7 & 0x7F), byte(n & 0x7F)}
+}
+
+func syncSafeInt(data []byte) uint32 {
+	return uint32(data[0]&0x7F)<<21 | uint32(data[1]&0x7F)<<14 | uint32(data[2]&0x7F)<<7 | uint32(data[3]&0x7F)
+}
@@ -0,0 +1,110 @@
+package id3_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"unicode/utf16"
+
+	"meowyplayer.com/utility/id3"
+)
+
+// split the tag into its frames by the frame ids
+func readFrames(t *testing.T, data []byte) map[string][]byte {
+	if string(data[:4]) != "ID3\x03" {
+		t.Fatalf("not an ID3v2.3 tag: %q\n", data[:4])
+	}
+	size := int(data[6])<<21 | int(data[7])<<14 | int(data[8])<<7 | int(data[9])
+	frames := map[string][]byte{}
+	for rest := data[10 : 10+size]; len(rest) > 0; {
+		frameSize := int(binary.BigEndian.Uint32(rest[4:8]))
+		frames[string(rest[:4])] = rest[10 : 10+frameSize]
+		rest = rest[10+frameSize:]
+	}
+	return frames
+}
+
+func decodeUTF16(t *testing.T, data []byte) string {
+	if !bytes.HasPrefix(data, []byte{0xFF, 0xFE}) {
+		t.Fatalf("missing the byte order mark: %v\n", data)
+	}
+	units := make([]uint16, 0, len(data)/2)
+	for i := 2; i+1 < len(data); i += 2 {
+		units = append(units, binary.LittleEndian.Uint16(data[i:]))
+	}
+	return string(utf16.Decode(units))
+}
+
+func TestEncode(t *testing.T) {
+	tag := id3.Tag{
+		Title:       "恋愛サーキュレーション",
+		Artist:      "Meowy & Friends",
+		URL:         "https://www.youtube.com/watch?v=auQxNYJ07Lc",
+		Picture:     []byte{0x89, 'P', 'N', 'G'},
+		PictureMIME: "image/png",
+	}
+	frames := readFrames(t, tag.Encode())
+
+	if title := decodeUTF16(t, frames["TIT2"][1:]); title != tag.Title {
+		t.Errorf("unexpected title: %v\n", title)
+	}
+	if artist := decodeUTF16(t, frames["TPE1"][1:]); artist != tag.Artist {
+		t.Errorf("unexpected artist: %v\n", artist)
+	}
+	if link := frames["WXXX"]; !bytes.HasSuffix(link, []byte("\x00\x00"+tag.URL)) {
+		t.Errorf("unexpected link: %q\n", link)
+	}
+	if comment := frames["COMM"]; string(comment[1:4]) != "eng" || decodeUTF16(t, comment[8:]) != tag.URL {
+		t.Errorf("unexpected comment: %q\n", comment)
+	}
+	if picture := frames["APIC"]; !bytes.Equal(picture, []byte("\x00image/png\x00\x03\x00\x89PNG")) {
+		t.Errorf("unexpected picture: %q\n", picture)
+	}
+}
+
+func TestEncodeLeavesOutEmptyFields(t *testing.T) {
+	frames := readFrames(t, (&id3.Tag{Title: "Mouso Express"}).Encode())
+	if len(frames) != 1 || frames["TIT2"] == nil {
+		t.Errorf("expected the title only, got %v\n", frames)
+	}
+}
+
+func TestWriteReplacesTag(t *testing.T) {
+	audio := []byte{0xFF, 0xFB, 0x90, 0x64}
+	tagged := id3.Write(&id3.Tag{Title: "Into The Light"}, audio)
+	retagged := id3.Write(&id3.Tag{Title: "Renai Circulation"}, tagged)
+
+	if !bytes.Equal(id3.Strip(retagged), audio) {
+		t.Fatalf("the audio is changed: %v\n", id3.Strip(retagged))
+	}
+	if title := decodeUTF16(t, readFrames(t, retagged)["TIT2"][1:]); title != "Renai Circulation" {
+		t.Errorf("the tag is not replaced: %v\n", title)
+	}
+}
+
+func TestStripWithoutTag(t *testing.T) {
+	audio := []byte{0xFF, 0xFB, 0x90, 0x64}
+	if !bytes.Equal(id3.Strip(audio), audio) {
+		t.Errorf("the untagged audio is changed\n")
+	}
+}
+
+func TestCopy(t *testing.T) {
+	audio := []byte{0xFF, 0xFB, 0x90, 0x64}
+	tests := [][]byte{
+		audio,
+		id3.Write(&id3.Tag{Title: "Into The Light"}, audio),
+		{},
+	}
+
+	for _, test := range tests {
+		copied := bytes.Buffer{}
+		tag := id3.Tag{Title: "Renai Circulation"}
+		if err := id3.Copy(&copied, &tag, bytes.NewReader(test)); err != nil {
+			t.Fatalf("%v\n", err)
+		}
+		if expected := id3.Write(&tag, test); !bytes.Equal(copied.Bytes(), expected) {
+			t.Errorf("expected %v, got %v\n", expected, copied.Bytes())
+		}
+	}
+}
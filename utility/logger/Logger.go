@@ -1,43 +1,124 @@
 package logger
 
 import (
+	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"os"
 	"runtime"
+	"slices"
 )
 
+var ErrUnknownFormat = errors.New("unknown log format")
+
+// the subsystems tagged on the log records
+const (
+	Player  = "player"
+	Network = "network"
+	Library = "library"
+)
+
+const subsystemKey = "subsystem"
+
+type Config struct {
+	Level   slog.Level
+	Format  string //text or json
+	File    string //empty to log to the error stream only
+	MaxSize int64  //the log file is rotated once it would grow past the size
+	Backups int    //the rotated files kept as log.txt.1, log.txt.2...
+}
+
+func DefaultConfig() Config {
+	return Config{Level: slog.LevelInfo, Format: "text", File: "log.txt", MaxSize: 4 << 20, Backups: 3}
+}
+
+// read the -log-level and -log-format flags, then log to the file and the error stream
 func Initiate() {
-	const kLogFileName = "log.txt"
-	fileLog := flag.Bool("filelog", true, "output logs to "+kLogFileName)
-	errLog := flag.Bool("errlog", true, "output logs to the error stream")
+	config := DefaultConfig()
+	flag.TextVar(&config.Level, "log-level", config.Level, "the lowest level logged: debug, info, warn or error")
+	flag.StringVar(&config.Format, "log-format", config.Format, "the log format: text or json")
 	flag.Parse()
 
-	//set up writing destination
-	loggers := []io.Writer{}
+	if err := Setup(&config); err != nil {
+		Error(err, "failed to initiate logger", 1)
+	}
+}
 
-	if *fileLog {
-		file, err := os.OpenFile(kLogFileName, os.O_CREATE|os.O_APPEND, 0777)
+// set the default logger, the log package is redirected to it as well
+func Setup(config *Config) error {
+	writers := []io.Writer{}
+	if config.File != "" {
+		file, err := NewRotatingWriter(config.File, config.MaxSize, config.Backups)
 		if err != nil {
-			Error(err, "failed to initiate logger", 1)
+			return err
 		}
-		loggers = append(loggers, file)
-	}
-
-	if *errLog {
-		loggers = append(loggers, os.Stderr)
+		writers = append(writers, file)
 	}
 
 	//if -H=windowsgui is set, then writing to Stderr stream will fails AND
 	//stops all the subsequence writing operations to the remaining writers
 	//hence log file should be written before the console output
-	log.SetOutput(io.MultiWriter(loggers...))
+	writers = append(writers, os.Stderr)
+
+	handler, err := NewHandler(io.MultiWriter(writers...), config)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(slog.New(handler))
+	return nil
 }
 
+func NewHandler(w io.Writer, config *Config) (slog.Handler, error) {
+	options := &slog.HandlerOptions{Level: config.Level}
+	switch config.Format {
+	case "text":
+		return slog.NewTextHandler(w, options), nil
+	case "json":
+		return slog.NewJSONHandler(w, options), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, config.Format)
+	}
+}
+
+// a logger tagged with the subsystem, it follows the default logger even if it is made before Setup
+func Subsystem(name string) *slog.Logger {
+	return slog.New(&defaultHandler{}).With(subsystemKey, name)
+}
+
+// log the error along with where it occurred, frameRewind 1 being the caller
 func Error(err error, message string, frameRewind int) {
-	_, file, line, ok := runtime.Caller(frameRewind)
-	if ok {
-		log.Printf("error occured at: %s:%d\n%v: %v\n", file, line, message, err)
+	_, file, line, _ := runtime.Caller(frameRewind)
+	slog.Error(message, "err", err, "at", fmt.Sprintf("%v:%v", file, line))
+}
+
+// the handler of the default logger at the time of logging, with the attributes and groups applied over it
+type defaultHandler struct {
+	wraps []func(slog.Handler) slog.Handler
+}
+
+func (h *defaultHandler) handler() slog.Handler {
+	handler := slog.Default().Handler()
+	for _, wrap := range h.wraps {
+		handler = wrap(handler)
 	}
+	return handler
+}
+
+func (h *defaultHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return slog.Default().Handler().Enabled(ctx, level)
+}
+
+func (h *defaultHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.handler().Handle(ctx, record)
+}
+
+func (h *defaultHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &defaultHandler{append(slices.Clip(h.wraps), func(handler slog.Handler) slog.Handler { return handler.WithAttrs(attrs) })}
+}
+
+func (h *defaultHandler) WithGroup(name string) slog.Handler {
+	return &defaultHandler{append(slices.Clip(h.wraps), func(handler slog.Handler) slog.Handler { return handler.WithGroup(name) })}
 }
@@ -0,0 +1,67 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"meowyplayer.com/utility/logger"
+)
+
+// send the default logger to the buffer for the test
+func captureLog(t *testing.T, config *logger.Config) *bytes.Buffer {
+	buffer := &bytes.Buffer{}
+	handler, err := logger.NewHandler(buffer, config)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	previous := slog.Default()
+	slog.SetDefault(slog.New(handler))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return buffer
+}
+
+func TestSubsystem(t *testing.T) {
+	//made before the default logger is set, as the package level loggers are
+	playerLog := logger.Subsystem(logger.Player)
+
+	config := logger.DefaultConfig()
+	config.Format = "json"
+	buffer := captureLog(t, &config)
+
+	playerLog.Info("playing", "title", "meow")
+	record := map[string]any{}
+	if err := json.Unmarshal(buffer.Bytes(), &record); err != nil {
+		t.Fatalf("%v: %q\n", err, buffer.String())
+	}
+	if record["subsystem"] != logger.Player || record["msg"] != "playing" || record["title"] != "meow" || record["level"] != "INFO" {
+		t.Errorf("unexpected record: %v\n", record)
+	}
+}
+
+func TestLevel(t *testing.T) {
+	config := logger.DefaultConfig()
+	config.Level = slog.LevelWarn
+	buffer := captureLog(t, &config)
+
+	networkLog := logger.Subsystem(logger.Network)
+	networkLog.Info("scraping")
+	log.Printf("legacy")
+	networkLog.Warn("reconnecting")
+
+	if text := buffer.String(); strings.Contains(text, "scraping") || strings.Contains(text, "legacy") || !strings.Contains(text, "level=WARN msg=reconnecting subsystem=network") {
+		t.Errorf("expected only the warning to be logged, got %q\n", text)
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	config := logger.DefaultConfig()
+	config.Format = "xml"
+	if _, err := logger.NewHandler(&bytes.Buffer{}, &config); !errors.Is(err, logger.ErrUnknownFormat) {
+		t.Errorf("expected %v, got %v\n", logger.ErrUnknownFormat, err)
+	}
+}
@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+/*
+Write to the file until it would grow past the size, then rotate it into path.1, path.1 into path.2 and so on.
+Only the given number of the rotated files are kept, the oldest one is dropped.
+*/
+type RotatingWriter struct {
+	mutex   sync.Mutex
+	path    string
+	maxSize int64
+	backups int
+	file    *os.File
+	size    int64
+}
+
+func NewRotatingWriter(path string, maxSize int64, backups int) (*RotatingWriter, error) {
+	w := &RotatingWriter{path: path, maxSize: maxSize, backups: backups}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *RotatingWriter) open() error {
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	w.file, w.size = file, info.Size()
+	return nil
+}
+
+// a record larger than the size still goes into a file of its own
+func (w *RotatingWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *RotatingWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+
+	//the renames fail harmlessly for the backups not created yet
+	os.Remove(backupPath(w.path, w.backups))
+	for i := w.backups - 1; i >= 1; i-- {
+		os.Rename(backupPath(w.path, i), backupPath(w.path, i+1))
+	}
+	if w.backups > 0 {
+		if err := os.Rename(w.path, backupPath(w.path, 1)); err != nil {
+			return err
+		}
+	} else if err := os.Remove(w.path); err != nil {
+		return err
+	}
+	return w.open()
+}
+
+func (w *RotatingWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.file.Close()
+}
+
+func backupPath(path string, index int) string {
+	return fmt.Sprintf("%v.%v", path, index)
+}
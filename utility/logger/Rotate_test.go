@@ -0,0 +1,81 @@
+package logger_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"meowyplayer.com/utility/logger"
+)
+
+func readFile(t *testing.T, path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	return string(data)
+}
+
+func TestRotatingWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.txt")
+	w, err := logger.NewRotatingWriter(path, 8, 2)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	defer w.Close()
+	write := func(records ...string) {
+		for _, record := range records {
+			if _, err := w.Write([]byte(record)); err != nil {
+				t.Fatalf("%v\n", err)
+			}
+		}
+	}
+
+	//the records fitting in the size share a file
+	write("aaa\n", "bbb\n", "cccc\n")
+	if log := readFile(t, path); log != "cccc\n" {
+		t.Errorf("unexpected log: %q\n", log)
+	}
+	if log := readFile(t, path+".1"); log != "aaa\nbbb\n" {
+		t.Errorf("unexpected backup: %q\n", log)
+	}
+
+	//the oldest file is dropped
+	write("dddd\n", "eeee\n")
+	if log := readFile(t, path); log != "eeee\n" {
+		t.Errorf("unexpected log: %q\n", log)
+	}
+	if log := readFile(t, path+".1"); log != "dddd\n" {
+		t.Errorf("unexpected first backup: %q\n", log)
+	}
+	if log := readFile(t, path+".2"); log != "cccc\n" {
+		t.Errorf("unexpected second backup: %q\n", log)
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected only 2 backups to be kept\n")
+	}
+}
+
+func TestRotatingWriterAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.txt")
+	if err := os.WriteFile(path, []byte("old\n"), 0666); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	w, err := logger.NewRotatingWriter(path, 6, 1)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	defer w.Close()
+
+	//the existing log counts towards the size
+	if _, err := w.Write([]byte("new\n")); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if log := readFile(t, path); log != "new\n" {
+		t.Errorf("unexpected log: %q\n", log)
+	}
+	if log := readFile(t, path+".1"); log != "old\n" {
+		t.Errorf("unexpected backup: %q\n", log)
+	}
+}
@@ -0,0 +1,9 @@
+package network
+
+import "errors"
+
+var (
+	ErrProviderNotFound  = errors.New("provider not registered")
+	ErrDuplicateProvider = errors.New("provider already registered")
+	ErrMissingScraper    = errors.New("provider lacks a scraper for its capability")
+)
@@ -0,0 +1,94 @@
+package network
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"meowyplayer.com/utility/network/downloader"
+	"meowyplayer.com/utility/network/scraper"
+)
+
+type Capability uint8
+
+const (
+	Search Capability = 1 << iota
+	PlayList
+	DirectURL
+)
+
+/*
+A site that can be searched and downloaded from.
+*/
+type Provider struct {
+	Name            string
+	Icon            fyne.Resource
+	VideoScraper    scraper.VideoScraper
+	PlayListScraper scraper.PlayListScraper
+	VideoResolver   scraper.VideoResolver
+	MusicDownloader downloader.MusicDownloader
+	Capability      Capability
+}
+
+func (p *Provider) Supports(capability Capability) bool {
+	return p.Capability&capability == capability
+}
+
+var registryLock sync.RWMutex
+var registry []Provider
+
+// register the provider, the name must be unique and every capability must be backed by a scraper
+func Register(provider Provider) error {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	if slices.ContainsFunc(registry, func(p Provider) bool { return p.Name == provider.Name }) {
+		return fmt.Errorf("%w: %v", ErrDuplicateProvider, provider.Name)
+	}
+	if provider.MusicDownloader == nil {
+		return fmt.Errorf("%w: %v has no music downloader", ErrMissingScraper, provider.Name)
+	}
+	if provider.Supports(Search) && provider.VideoScraper == nil {
+		return fmt.Errorf("%w: %v has no video scraper", ErrMissingScraper, provider.Name)
+	}
+	if provider.Supports(PlayList) && provider.PlayListScraper == nil {
+		return fmt.Errorf("%w: %v has no play list scraper", ErrMissingScraper, provider.Name)
+	}
+	if provider.Supports(DirectURL) && provider.VideoResolver == nil {
+		return fmt.Errorf("%w: %v has no video resolver", ErrMissingScraper, provider.Name)
+	}
+	registry = append(registry, provider)
+	return nil
+}
+
+func Unregister(name string) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	registry = slices.DeleteFunc(registry, func(p Provider) bool { return p.Name == name })
+}
+
+// find the provider by the name, fail with ErrProviderNotFound
+func Find(name string) (Provider, error) {
+	if provider, ok := Lookup(name); ok {
+		return provider, nil
+	}
+	return Provider{}, fmt.Errorf("%w: %v", ErrProviderNotFound, name)
+}
+
+func Lookup(name string) (Provider, bool) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	index := slices.IndexFunc(registry, func(p Provider) bool { return p.Name == name })
+	if index == -1 {
+		return Provider{}, false
+	}
+	return registry[index], true
+}
+
+// return the providers in the registration order
+func Providers() []Provider {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	return slices.Clone(registry)
+}
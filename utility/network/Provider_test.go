@@ -0,0 +1,57 @@
+package network_test
+
+import (
+	"errors"
+	"testing"
+
+	"meowyplayer.com/utility/network"
+	"meowyplayer.com/utility/network/downloader"
+	"meowyplayer.com/utility/network/scraper"
+)
+
+func TestRegister(t *testing.T) {
+	provider := network.Provider{
+		Name:            "Test",
+		VideoScraper:    scraper.NewBiliBiliScraper(scraper.BiliBiliHost),
+		MusicDownloader: downloader.NewBiliBiliDownloader(scraper.BiliBiliHost),
+		Capability:      network.Search,
+	}
+	if err := network.Register(provider); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	defer network.Unregister(provider.Name)
+
+	if err := network.Register(provider); !errors.Is(err, network.ErrDuplicateProvider) {
+		t.Errorf("expected %v, got %v\n", network.ErrDuplicateProvider, err)
+	}
+	if found, ok := network.Lookup(provider.Name); !ok || !found.Supports(network.Search) || found.Supports(network.PlayList) {
+		t.Errorf("unexpected provider: %+v\n", found)
+	}
+}
+
+func TestRegisterMissingScraper(t *testing.T) {
+	provider := network.Provider{
+		Name:            "Test",
+		MusicDownloader: downloader.NewBiliBiliDownloader(scraper.BiliBiliHost),
+		Capability:      network.Search | network.PlayList,
+	}
+	if err := network.Register(provider); !errors.Is(err, network.ErrMissingScraper) {
+		network.Unregister(provider.Name)
+		t.Fatalf("expected %v, got %v\n", network.ErrMissingScraper, err)
+	}
+}
+
+func TestFind(t *testing.T) {
+	provider := network.Provider{Name: "Test", MusicDownloader: downloader.NewBiliBiliDownloader(scraper.BiliBiliHost)}
+	if err := network.Register(provider); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	defer network.Unregister(provider.Name)
+
+	if found, err := network.Find(provider.Name); err != nil || found.Name != provider.Name {
+		t.Errorf("unexpected provider: %+v %v\n", found, err)
+	}
+	if _, err := network.Find("Missing"); !errors.Is(err, network.ErrProviderNotFound) {
+		t.Errorf("expected %v, got %v\n", network.ErrProviderNotFound, err)
+	}
+}
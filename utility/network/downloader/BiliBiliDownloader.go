@@ -0,0 +1,121 @@
+package downloader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"meowyplayer.com/utility/network/fileformat"
+	"meowyplayer.com/utility/network/httpclient"
+)
+
+// https://app.quicktype.io/
+type bilibiliViewResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    struct {
+		Cid int64 `json:"cid"`
+	} `json:"data"`
+}
+
+type bilibiliPlayURLResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    struct {
+		Dash struct {
+			Audio []bilibiliAudioStream `json:"audio"`
+		} `json:"dash"`
+	} `json:"data"`
+}
+
+type bilibiliAudioStream struct {
+	ID        int    `json:"id"`
+	BaseURL   string `json:"baseUrl"`
+	Bandwidth int    `json:"bandwidth"`
+	Codecs    string `json:"codecs"`
+}
+
+/*
+Extract the audio stream from the BiliBili dash play url.
+The audio stream is AAC in an MP4 container rather than MP3.
+*/
+type BiliBiliDownloader struct {
+	host string
+}
+
+func NewBiliBiliDownloader(host string) *BiliBiliDownloader {
+	return &BiliBiliDownloader{host}
+}
+
+func (d *BiliBiliDownloader) Download(ctx context.Context, video *fileformat.VideoResult) ([]byte, error) {
+	stream, err := d.ResolveStream(ctx, video)
+	if err != nil {
+		return nil, err
+	}
+
+	networkLog.Debug("downloading music file", "title", video.Title)
+	defer networkLog.Debug("completed downloading", "title", video.Title)
+	return httpclient.DownloadAll(ctx, stream.URL, stream.Header)
+}
+
+func (d *BiliBiliDownloader) ResolveStream(ctx context.Context, video *fileformat.VideoResult) (*Stream, error) {
+	cid, err := d.getCID(ctx, video)
+	if err != nil {
+		return nil, err
+	}
+	audioURL, err := d.getAudioURL(ctx, video, cid)
+	if err != nil {
+		return nil, err
+	}
+	return &Stream{URL: audioURL, Header: bilibiliHeader()}, nil
+}
+
+// BiliBili rejects the requests without the referer
+func bilibiliHeader() http.Header {
+	return http.Header{"Referer": {"https://www.bilibili.com"}}
+}
+
+func (d *BiliBiliDownloader) getJSON(ctx context.Context, rawURL string, object any) error {
+	resp, err := httpclient.Get(ctx, rawURL, bilibiliHeader())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(object)
+}
+
+// the content id of the first page of the video
+func (d *BiliBiliDownloader) getCID(ctx context.Context, video *fileformat.VideoResult) (int64, error) {
+	networkLog.Debug("fetching BiliBili content id", "title", video.Title)
+	viewResp := bilibiliViewResponse{}
+	if err := d.getJSON(ctx, d.host+`/x/web-interface/view?`+url.Values{"bvid": {video.VideoID}}.Encode(), &viewResp); err != nil {
+		return 0, err
+	}
+	if viewResp.Code != 0 {
+		return 0, fmt.Errorf("couldn't get the content id of %v (%v): %v", video.Title, viewResp.Code, viewResp.Message)
+	}
+	return viewResp.Data.Cid, nil
+}
+
+// the audio stream with the highest bandwidth
+func (d *BiliBiliDownloader) getAudioURL(ctx context.Context, video *fileformat.VideoResult, cid int64) (string, error) {
+	networkLog.Debug("fetching BiliBili audio stream", "title", video.Title)
+	query := url.Values{"bvid": {video.VideoID}, "cid": {fmt.Sprint(cid)}, "fnval": {"16"}}
+	playURLResp := bilibiliPlayURLResponse{}
+	if err := d.getJSON(ctx, d.host+`/x/player/playurl?`+query.Encode(), &playURLResp); err != nil {
+		return "", err
+	}
+	if playURLResp.Code != 0 {
+		return "", fmt.Errorf("couldn't get the audio stream of %v (%v): %v", video.Title, playURLResp.Code, playURLResp.Message)
+	}
+
+	audio := playURLResp.Data.Dash.Audio
+	if len(audio) == 0 {
+		return "", fmt.Errorf("%v has no audio stream", video.Title)
+	}
+	best := slices.MaxFunc(audio, func(a1, a2 bilibiliAudioStream) int { return a1.Bandwidth - a2.Bandwidth })
+	return best.BaseURL, nil
+}
@@ -0,0 +1,80 @@
+package downloader_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"meowyplayer.com/utility/network/downloader"
+	"meowyplayer.com/utility/network/fileformat"
+)
+
+// replay the recorded api responses, the audio streams are served by the local server
+func newBiliBiliServer(t *testing.T) *httptest.Server {
+	view, err := os.ReadFile("testdata/bilibili_view.json")
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	playURL, err := os.ReadFile("testdata/bilibili_playurl.json")
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Referer") == "" {
+			http.Error(w, "missing referer", http.StatusForbidden)
+			return
+		}
+		switch {
+		case r.URL.Path == "/x/web-interface/view" && r.URL.Query().Get("bvid") == "BV17x411w7KC":
+			w.Write(view)
+		case r.URL.Path == "/x/web-interface/view":
+			w.Write([]byte(`{"code":-404,"message":"video not found"}`))
+		case r.URL.Path == "/x/player/playurl" && r.URL.Query().Get("cid") == "279786":
+			w.Write([]byte(strings.ReplaceAll(string(playURL), "{{host}}", server.URL)))
+		case strings.HasPrefix(r.URL.Path, "/upgcxcode/"):
+			w.Write([]byte(strings.TrimPrefix(r.URL.Path, "/upgcxcode/")))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestBiliBiliDownload(t *testing.T) {
+	server := newBiliBiliServer(t)
+	data, err := downloader.NewBiliBiliDownloader(server.URL).Download(context.Background(), &fileformat.VideoResult{Title: "Renai Circulation", VideoID: "BV17x411w7KC"})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	//the stream with the highest bandwidth is picked
+	if string(data) != "audio-192k.m4s" {
+		t.Fatalf("unexpected audio stream: %v\n", string(data))
+	}
+}
+
+func TestBiliBiliDownloadNotFound(t *testing.T) {
+	server := newBiliBiliServer(t)
+	if _, err := downloader.NewBiliBiliDownloader(server.URL).Download(context.Background(), &fileformat.VideoResult{Title: "Missing", VideoID: "BV1missing"}); err == nil {
+		t.Fatalf("expected an error for the missing video\n")
+	}
+}
+
+func TestBiliBiliResolveStream(t *testing.T) {
+	server := newBiliBiliServer(t)
+	stream, err := downloader.NewBiliBiliDownloader(server.URL).ResolveStream(context.Background(), &fileformat.VideoResult{Title: "Renai Circulation", VideoID: "BV17x411w7KC"})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	//the stream is fetched by the download manager, so it carries the referer along
+	if stream.URL != server.URL+"/upgcxcode/audio-192k.m4s" || stream.Header.Get("Referer") == "" {
+		t.Errorf("unexpected stream: %+v\n", stream)
+	}
+}
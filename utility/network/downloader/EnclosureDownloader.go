@@ -0,0 +1,35 @@
+package downloader
+
+import (
+	"context"
+	"errors"
+
+	"meowyplayer.com/utility/network/fileformat"
+	"meowyplayer.com/utility/network/httpclient"
+)
+
+var ErrNoAudio = errors.New("the video has no audio file to download")
+
+// fetch the audio file linked by the result itself, e.g. the enclosure of a podcast episode
+type EnclosureDownloader struct{}
+
+func NewEnclosureDownloader() *EnclosureDownloader {
+	return &EnclosureDownloader{}
+}
+
+func (d *EnclosureDownloader) Download(ctx context.Context, video *fileformat.VideoResult) ([]byte, error) {
+	stream, err := d.ResolveStream(ctx, video)
+	if err != nil {
+		return nil, err
+	}
+
+	networkLog.Debug("downloading enclosure", "url", stream.URL)
+	return httpclient.DownloadAll(ctx, stream.URL, stream.Header)
+}
+
+func (d *EnclosureDownloader) ResolveStream(ctx context.Context, video *fileformat.VideoResult) (*Stream, error) {
+	if video.AudioURL == "" {
+		return nil, ErrNoAudio
+	}
+	return &Stream{URL: video.AudioURL}, nil
+}
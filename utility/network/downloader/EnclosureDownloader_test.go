@@ -0,0 +1,37 @@
+package downloader_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"meowyplayer.com/utility/network/downloader"
+	"meowyplayer.com/utility/network/fileformat"
+)
+
+func TestEnclosureDownload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/audio/1.mp3" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("episode 1"))
+	}))
+	defer server.Close()
+
+	data, err := downloader.NewEnclosureDownloader().Download(context.Background(), &fileformat.VideoResult{AudioURL: server.URL + "/audio/1.mp3"})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if string(data) != "episode 1" {
+		t.Errorf("unexpected audio: %q\n", data)
+	}
+}
+
+func TestEnclosureMissing(t *testing.T) {
+	if _, err := downloader.NewEnclosureDownloader().ResolveStream(context.Background(), &fileformat.VideoResult{VideoID: "auQxNYJ07Lc"}); !errors.Is(err, downloader.ErrNoAudio) {
+		t.Errorf("expected %v, got %v\n", downloader.ErrNoAudio, err)
+	}
+}
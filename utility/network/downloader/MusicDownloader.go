@@ -1,7 +1,38 @@
 package downloader
 
-import "meowyplayer.com/utility/network/fileformat"
+import (
+	"context"
+	"net/http"
+
+	"meowyplayer.com/utility/logger"
+	"meowyplayer.com/utility/network/fileformat"
+	"meowyplayer.com/utility/network/httpclient"
+)
+
+var networkLog = logger.Subsystem(logger.Network)
 
 type MusicDownloader interface {
-	Download(video *fileformat.VideoResult) ([]byte, error)
+	Download(ctx context.Context, video *fileformat.VideoResult) ([]byte, error)
+}
+
+// where the audio of a video can be fetched from
+type Stream struct {
+	URL    string
+	Header http.Header
+}
+
+/*
+A downloader that can hand over the audio stream, so that the stream can be
+fetched with progress and resumed instead of being read in one go.
+*/
+type StreamResolver interface {
+	ResolveStream(ctx context.Context, video *fileformat.VideoResult) (*Stream, error)
+}
+
+/*
+A downloader that writes the audio into the file by itself, e.g. through an external program,
+reporting the progress as it goes.
+*/
+type FileDownloader interface {
+	DownloadFile(ctx context.Context, video *fileformat.VideoResult, path string, onProgress func(httpclient.Progress)) error
 }
@@ -1,16 +1,15 @@
 package downloader
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
-	"net/http"
 	"net/url"
 	"regexp"
 
-	"meowyplayer.com/utility/assert"
 	"meowyplayer.com/utility/network/fileformat"
+	"meowyplayer.com/utility/network/httpclient"
 )
 
 // https://app.quicktype.io/
@@ -31,32 +30,43 @@ type Y2MateDownloader struct {
 
 func NewY2MateDownloader() *Y2MateDownloader {
 	const keyPattern = `"f":"mp3","q":"128kbps","q_text":"MP3 - 128kbps","k":"([\w\/\\]+)"`
-	keyRegex, err := regexp.Compile(keyPattern)
-	assert.NoErr(err, "failed to compile Y2Mate downloader key regex")
-	return &Y2MateDownloader{keyRegex}
+	return &Y2MateDownloader{regexp.MustCompile(keyPattern)}
 }
 
-func (d *Y2MateDownloader) Download(video *fileformat.VideoResult) ([]byte, error) {
-	key, err := d.getConverterKey(video)
+func (d *Y2MateDownloader) Download(ctx context.Context, video *fileformat.VideoResult) ([]byte, error) {
+	stream, err := d.ResolveStream(ctx, video)
 	if err != nil {
 		return nil, err
 	}
 
-	defer log.Printf("completed downloading\n")
-	return d.getMusicContent(video, key)
+	networkLog.Debug("downloading music file", "title", video.Title)
+	defer networkLog.Debug("completed downloading", "title", video.Title)
+	return httpclient.DownloadAll(ctx, stream.URL, stream.Header)
 }
 
-func (d *Y2MateDownloader) getConverterKey(video *fileformat.VideoResult) (string, error) {
+func (d *Y2MateDownloader) ResolveStream(ctx context.Context, video *fileformat.VideoResult) (*Stream, error) {
+	key, err := d.getConverterKey(ctx, video)
+	if err != nil {
+		return nil, err
+	}
+	link, err := d.getMusicLink(ctx, video, key)
+	if err != nil {
+		return nil, err
+	}
+	return &Stream{URL: link}, nil
+}
+
+func (d *Y2MateDownloader) getConverterKey(ctx context.Context, video *fileformat.VideoResult) (string, error) {
 	const (
 		converterUrl = `https://www.y2mate.com/mates/analyzeV2/ajax`
 		youtubeUrl   = `https://www.youtube.com/watch?`
 	)
-	log.Printf("fetching y2mate converter key...\n")
+	networkLog.Debug("fetching y2mate converter key", "title", video.Title)
 
 	//request the content that contains converter key
 	videoUrl := youtubeUrl + url.Values{"v": {video.VideoID}}.Encode()
 	queryData := url.Values{"k_query": {videoUrl}, "k_page": {"home"}, "hl": {"en"}, "q_auto": {"1"}}
-	resp, err := http.PostForm(converterUrl, queryData)
+	resp, err := httpclient.PostForm(ctx, converterUrl, queryData)
 	if err != nil {
 		return "", err
 	}
@@ -75,34 +85,31 @@ func (d *Y2MateDownloader) getConverterKey(video *fileformat.VideoResult) (strin
 	return matches[1], nil
 }
 
-func (d *Y2MateDownloader) getMusicContent(video *fileformat.VideoResult, converterKey string) ([]byte, error) {
+func (d *Y2MateDownloader) getMusicLink(ctx context.Context, video *fileformat.VideoResult, converterKey string) (string, error) {
 	const dbURL = `https://www.y2mate.com/mates/convertV2/index`
 
-	log.Printf("downloading music file...\n")
+	networkLog.Debug("converting music file", "title", video.Title)
 
 	//request for video -> mp3 conversion
 	queryData := url.Values{"vid": {video.VideoID}, "k": {converterKey}}
-	resp, err := http.PostForm(dbURL, queryData)
+	resp, err := httpclient.PostForm(ctx, dbURL, queryData)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 	defer resp.Body.Close()
 
 	//parse json response
 	data, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 	converterResp := y2mateConverterResponse{}
 	if err := json.Unmarshal(data, &converterResp); err != nil {
-		return nil, err
+		return "", err
 	}
 
-	//fetch music file
-	resp, err = http.Get(converterResp.Dlink)
-	if err != nil {
-		return nil, err
+	if converterResp.Dlink == "" {
+		return "", fmt.Errorf("couldn't convert the music: %v", video.Title)
 	}
-	defer resp.Body.Close()
-	return io.ReadAll(resp.Body)
+	return converterResp.Dlink, nil
 }
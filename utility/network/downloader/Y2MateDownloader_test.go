@@ -1,6 +1,7 @@
 package downloader_test
 
 import (
+	"context"
 	"testing"
 
 	"meowyplayer.com/utility/network/downloader"
@@ -23,7 +24,7 @@ func TestIntoTheLight(t *testing.T) {
 }
 
 func DownloadQuery(downloader downloader.MusicDownloader, video *fileformat.VideoResult, t *testing.T) {
-	_, err := downloader.Download(video)
+	_, err := downloader.Download(context.Background(), video)
 	if err != nil {
 		t.Fatalf("%v\n", err)
 	}
@@ -0,0 +1,186 @@
+package downloader
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"meowyplayer.com/utility/network/fileformat"
+	"meowyplayer.com/utility/network/httpclient"
+)
+
+var ErrBinaryNotFound = errors.New("the program is not installed or not in PATH")
+
+var (
+	YtDlpFormats  = []string{"mp3"} //the player only decodes mp3
+	YtDlpBitrates = []int{128, 192, 256, 320}
+)
+
+type YtDlpOptions struct {
+	Format  string `json:"format"`
+	Bitrate int    `json:"bitrate"` //in kbps
+}
+
+func DefaultYtDlpOptions() YtDlpOptions {
+	return YtDlpOptions{Format: "mp3", Bitrate: 192}
+}
+
+func (o *YtDlpOptions) Validate() error {
+	if !slices.Contains(YtDlpFormats, o.Format) {
+		return fmt.Errorf("unsupported audio format %q", o.Format)
+	}
+	if !slices.Contains(YtDlpBitrates, o.Bitrate) {
+		return fmt.Errorf("unsupported bitrate %v kbps", o.Bitrate)
+	}
+	return nil
+}
+
+/*
+Extract the audio with the locally installed yt-dlp, which converts it with ffmpeg.
+The programs are looked up in PATH on every download, so that they can be installed while the player runs.
+https://github.com/yt-dlp/yt-dlp
+*/
+type YtDlpDownloader struct {
+	lock    sync.Mutex
+	options YtDlpOptions
+}
+
+func NewYtDlpDownloader(options YtDlpOptions) (*YtDlpDownloader, error) {
+	d := &YtDlpDownloader{}
+	return d, d.Configure(options)
+}
+
+// the invalid options are rejected and the previous ones are kept
+func (d *YtDlpDownloader) Configure(options YtDlpOptions) error {
+	if err := options.Validate(); err != nil {
+		return err
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.options = options
+	return nil
+}
+
+func (d *YtDlpDownloader) Download(ctx context.Context, video *fileformat.VideoResult) ([]byte, error) {
+	file, err := os.CreateTemp("", "yt-dlp-*.audio")
+	if err != nil {
+		return nil, err
+	}
+	file.Close()
+	defer os.Remove(file.Name())
+
+	if err := d.DownloadFile(ctx, video, file.Name(), func(httpclient.Progress) {}); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(file.Name())
+}
+
+func (d *YtDlpDownloader) DownloadFile(ctx context.Context, video *fileformat.VideoResult, path string, onProgress func(httpclient.Progress)) error {
+	ytDlp, err := lookPath("yt-dlp")
+	if err != nil {
+		return err
+	}
+	ffmpeg, err := lookPath("ffmpeg")
+	if err != nil {
+		return err
+	}
+
+	//yt-dlp names the output by the extension, it is written into a scratch folder next to the path
+	folder, err := os.MkdirTemp(filepath.Dir(path), "yt-dlp-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(folder)
+
+	d.lock.Lock()
+	options := d.options
+	d.lock.Unlock()
+
+	//the bare ids are understood as YouTube videos
+	source := video.URL
+	if source == "" {
+		source = video.VideoID
+	}
+	args := []string{
+		"--no-playlist", "--newline", "--no-colors",
+		"--progress-template", "download:%(progress.downloaded_bytes)s/%(progress.total_bytes,progress.total_bytes_estimate)s/%(progress.speed)s",
+		"--extract-audio", "--audio-format", options.Format, "--audio-quality", fmt.Sprintf("%vK", options.Bitrate),
+		"--ffmpeg-location", ffmpeg,
+		"--output", filepath.Join(folder, "audio.%(ext)s"),
+		"--", source,
+	}
+	networkLog.Debug("extracting audio", "source", source, "command", ytDlp+" "+strings.Join(args, " "))
+	if err := runYtDlp(ctx, ytDlp, args, onProgress); err != nil {
+		return err
+	}
+	return os.Rename(filepath.Join(folder, "audio."+options.Format), path)
+}
+
+func lookPath(name string) (string, error) {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrBinaryNotFound, name)
+	}
+	return path, nil
+}
+
+// the progress is read from the output line by line, the errors are kept for the report
+func runYtDlp(ctx context.Context, ytDlp string, args []string, onProgress func(httpclient.Progress)) error {
+	cmd := exec.CommandContext(ctx, ytDlp, args...)
+	output, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	cmd.Stderr = cmd.Stdout
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	errorLines := []string{}
+	scanner := bufio.NewScanner(output)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if progress, ok := parseYtDlpProgress(line); ok {
+			onProgress(progress)
+		} else if strings.HasPrefix(line, "ERROR:") {
+			errorLines = append(errorLines, strings.TrimSpace(strings.TrimPrefix(line, "ERROR:")))
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if len(errorLines) > 0 {
+			return fmt.Errorf("yt-dlp failed (%w): %v", err, strings.Join(errorLines, "; "))
+		}
+		return fmt.Errorf("yt-dlp failed: %w", err)
+	}
+	return nil
+}
+
+// read "download:<received>/<total>/<speed>", the unknown fields are "NA"
+func parseYtDlpProgress(line string) (httpclient.Progress, bool) {
+	fields := strings.Split(strings.TrimPrefix(line, "download:"), "/")
+	if !strings.HasPrefix(line, "download:") || len(fields) != 3 {
+		return httpclient.Progress{}, false
+	}
+	received, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return httpclient.Progress{}, false
+	}
+	total, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		total = -1
+	}
+	speed, _ := strconv.ParseFloat(fields[2], 64)
+	return httpclient.Progress{Received: int64(received), Total: int64(total), Speed: speed}, true
+}
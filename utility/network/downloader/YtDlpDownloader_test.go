@@ -0,0 +1,147 @@
+package downloader_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"meowyplayer.com/utility/network/downloader"
+	"meowyplayer.com/utility/network/fileformat"
+	"meowyplayer.com/utility/network/httpclient"
+)
+
+// the fake yt-dlp records its arguments, reports the progress and writes the audio named by the format
+// only the shell builtins are used, since PATH holds nothing but the fake programs
+const fakeYtDlp = `#!/bin/sh
+echo "$@" > "${0%/*}/args"
+while [ $# -gt 0 ]; do
+	case "$1" in
+	--output) output="$2"; shift ;;
+	--audio-format) format="$2"; shift ;;
+	--) source="$2"; shift ;;
+	esac
+	shift
+done
+if [ "$source" = "unavailable" ]; then
+	echo "[youtube] unavailable: Downloading webpage"
+	echo "ERROR: [youtube] unavailable: Video unavailable" >&2
+	exit 1
+fi
+echo "[youtube] $source: Downloading webpage"
+echo "download:512/1024/256.5"
+echo "download:1024/NA/NA"
+printf "audio of %s" "$source" > "${output%"%(ext)s"}$format"
+`
+
+// make the fake programs the only ones in PATH
+func installFakePrograms(t *testing.T, programs map[string]string) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake programs are shell scripts")
+	}
+	folder := t.TempDir()
+	for name, script := range programs {
+		if err := os.WriteFile(filepath.Join(folder, name), []byte(script), 0755); err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}
+	t.Setenv("PATH", folder)
+	return folder
+}
+
+func newYtDlpDownloader(t *testing.T, options downloader.YtDlpOptions) *downloader.YtDlpDownloader {
+	d, err := downloader.NewYtDlpDownloader(options)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	return d
+}
+
+func TestYtDlpDownloadFile(t *testing.T) {
+	folder := installFakePrograms(t, map[string]string{"yt-dlp": fakeYtDlp, "ffmpeg": "#!/bin/sh\n"})
+	d := newYtDlpDownloader(t, downloader.YtDlpOptions{Format: "mp3", Bitrate: 256})
+
+	path := filepath.Join(t.TempDir(), "music.part")
+	progress := []httpclient.Progress{}
+	video := fileformat.VideoResult{VideoID: "auQxNYJ07Lc", URL: "https://www.youtube.com/watch?v=auQxNYJ07Lc"}
+	if err := d.DownloadFile(context.Background(), &video, path, func(p httpclient.Progress) { progress = append(progress, p) }); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if string(data) != "audio of "+video.URL {
+		t.Errorf("unexpected audio: %q\n", data)
+	}
+	expected := []httpclient.Progress{{Received: 512, Total: 1024, Speed: 256.5}, {Received: 1024, Total: -1, Speed: 0}}
+	if len(progress) != len(expected) || progress[0] != expected[0] || progress[1] != expected[1] {
+		t.Errorf("unexpected progress: %+v\n", progress)
+	}
+
+	args, err := os.ReadFile(filepath.Join(folder, "args"))
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	for _, arg := range []string{"--audio-format mp3", "--audio-quality 256K", "--ffmpeg-location " + filepath.Join(folder, "ffmpeg")} {
+		if !strings.Contains(string(args), arg) {
+			t.Errorf("missing %q in the arguments: %s\n", arg, args)
+		}
+	}
+
+	//the scratch folder is cleaned up
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("expected only the audio to be left, got %v files\n", len(entries))
+	}
+}
+
+func TestYtDlpDownloadBareID(t *testing.T) {
+	installFakePrograms(t, map[string]string{"yt-dlp": fakeYtDlp, "ffmpeg": "#!/bin/sh\n"})
+	data, err := newYtDlpDownloader(t, downloader.DefaultYtDlpOptions()).Download(context.Background(), &fileformat.VideoResult{VideoID: "y2XArpEcygc"})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if string(data) != "audio of y2XArpEcygc" {
+		t.Errorf("unexpected audio: %q\n", data)
+	}
+}
+
+func TestYtDlpMissingBinary(t *testing.T) {
+	tests := []struct {
+		programs map[string]string
+		missing  string
+	}{
+		{map[string]string{"ffmpeg": "#!/bin/sh\n"}, "yt-dlp"},
+		{map[string]string{"yt-dlp": fakeYtDlp}, "ffmpeg"},
+	}
+
+	for _, test := range tests {
+		installFakePrograms(t, test.programs)
+		_, err := newYtDlpDownloader(t, downloader.DefaultYtDlpOptions()).Download(context.Background(), &fileformat.VideoResult{VideoID: "y2XArpEcygc"})
+		if !errors.Is(err, downloader.ErrBinaryNotFound) || !strings.Contains(err.Error(), test.missing) {
+			t.Errorf("expected %v to be missing, got %v\n", test.missing, err)
+		}
+	}
+}
+
+func TestYtDlpFailure(t *testing.T) {
+	installFakePrograms(t, map[string]string{"yt-dlp": fakeYtDlp, "ffmpeg": "#!/bin/sh\n"})
+	_, err := newYtDlpDownloader(t, downloader.DefaultYtDlpOptions()).Download(context.Background(), &fileformat.VideoResult{VideoID: "unavailable"})
+	if err == nil || !strings.Contains(err.Error(), "Video unavailable") {
+		t.Errorf("expected the error of yt-dlp to be reported, got %v\n", err)
+	}
+}
+
+func TestYtDlpInvalidOptions(t *testing.T) {
+	if _, err := downloader.NewYtDlpDownloader(downloader.YtDlpOptions{Format: "m4a", Bitrate: 192}); err == nil {
+		t.Errorf("expected the format to be rejected\n")
+	}
+	d := newYtDlpDownloader(t, downloader.DefaultYtDlpOptions())
+	if err := d.Configure(downloader.YtDlpOptions{Format: "mp3", Bitrate: 100}); err == nil {
+		t.Errorf("expected the bitrate to be rejected\n")
+	}
+}
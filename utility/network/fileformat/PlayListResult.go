@@ -0,0 +1,11 @@
+package fileformat
+
+type PlayListResult struct {
+	PlayListID   string
+	ChannelID    string
+	ChannelTitle string
+	Title        string
+	ThumbnailURL string
+	Videos       []VideoResult
+	Truncated    bool //only the latest videos are listed, the older ones are left out
+}
@@ -2,17 +2,19 @@ package fileformat
 
 import (
 	"time"
-
-	"fyne.io/fyne/v2"
 )
 
 type VideoResult struct {
 	VideoID      string
+	URL          string //the page of the video on its site
 	ChannelID    string
 	ChannelTitle string
 	Title        string
 	Stats        string
 	Description  string
 	Length       time.Duration
-	Thumbnail    fyne.Resource
+	Views        int64     //zero if unknown
+	Uploaded     time.Time //zero if unknown
+	ThumbnailURL string
+	AudioURL     string //the audio file itself, e.g. the enclosure of a podcast episode
 }
@@ -0,0 +1,133 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+type Config struct {
+	Timeout   time.Duration `json:"timeout"`   //zero means no timeout
+	UserAgent string        `json:"userAgent"` //empty keeps the go default
+	Proxy     string        `json:"proxy"`     //empty uses the proxy from the environment
+}
+
+func DefaultConfig() Config {
+	return Config{Timeout: 30 * time.Second, UserAgent: "Mozilla/5.0"}
+}
+
+// set the user agent of every request, unless the request sets its own
+type userAgentTransport struct {
+	base      http.RoundTripper
+	userAgent string
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.userAgent != "" && req.Header.Get("User-Agent") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+	return t.base.RoundTrip(req)
+}
+
+var clientLock sync.RWMutex
+var sharedClient = newClient(DefaultConfig(), http.ProxyFromEnvironment)
+
+func newClient(config Config, proxy func(*http.Request) (*url.URL, error)) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	return &http.Client{Timeout: config.Timeout, Transport: &userAgentTransport{transport, config.UserAgent}}
+}
+
+// replace the shared client, the requests in flight keep the old one
+func Configure(config Config) error {
+	proxy := http.ProxyFromEnvironment
+	if config.Proxy != "" {
+		proxyURL, err := url.Parse(config.Proxy)
+		if err != nil {
+			return fmt.Errorf("invalid proxy %v: %w", config.Proxy, err)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	clientLock.Lock()
+	defer clientLock.Unlock()
+	sharedClient = newClient(config, proxy)
+	return nil
+}
+
+func Client() *http.Client {
+	clientLock.RLock()
+	defer clientLock.RUnlock()
+	return sharedClient
+}
+
+// the shared client without the total timeout, for the responses that never end such as the radio streams
+func StreamClient() *http.Client {
+	client := *Client()
+	client.Timeout = 0
+	return &client
+}
+
+// send the request with the shared client, the responses other than 2xx are errors
+func Do(req *http.Request) (*http.Response, error) {
+	resp, err := Client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || 300 <= resp.StatusCode {
+		resp.Body.Close()
+		return nil, newStatusError(req.URL.String(), resp)
+	}
+	return resp, nil
+}
+
+func Get(ctx context.Context, rawURL string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	return Do(req)
+}
+
+func PostForm(ctx context.Context, rawURL string, data url.Values) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return Do(req)
+}
+
+func ReadAll(ctx context.Context, rawURL string, header http.Header) ([]byte, error) {
+	resp, err := Get(ctx, rawURL, header)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// the cancellable counterpart of fyne.LoadResourceFromURLString
+func LoadResource(ctx context.Context, rawURL string) (fyne.Resource, error) {
+	data, err := ReadAll(ctx, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return fyne.NewStaticResource(path.Base(u.Path), data), nil
+}
@@ -0,0 +1,69 @@
+package httpclient_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"meowyplayer.com/utility/network/httpclient"
+)
+
+func TestUserAgent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("User-Agent")))
+	}))
+	defer server.Close()
+
+	config := httpclient.DefaultConfig()
+	config.UserAgent = "MeowyPlayer"
+	if err := httpclient.Configure(config); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	defer httpclient.Configure(httpclient.DefaultConfig())
+
+	data, err := httpclient.ReadAll(context.Background(), server.URL, nil)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if string(data) != "MeowyPlayer" {
+		t.Errorf("unexpected user agent: %v\n", string(data))
+	}
+}
+
+func TestCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if _, err := httpclient.ReadAll(ctx, server.URL, nil); err == nil {
+		t.Fatalf("expected an error for the cancelled request\n")
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	_, err := httpclient.ReadAll(context.Background(), server.URL, nil)
+	statusErr := &httpclient.StatusError{}
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("expected a status error for the missing page, got %v\n", err)
+	}
+	if statusErr.StatusCode != http.StatusNotFound || statusErr.URL != server.URL {
+		t.Errorf("unexpected status error: %+v\n", statusErr)
+	}
+}
+
+func TestInvalidProxy(t *testing.T) {
+	config := httpclient.DefaultConfig()
+	config.Proxy = "://proxy"
+	if err := httpclient.Configure(config); err == nil {
+		t.Fatalf("expected an error for the invalid proxy\n")
+	}
+}
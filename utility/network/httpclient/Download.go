@@ -0,0 +1,179 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// an idle connection is given up on after the timeout of the shared client
+// a download is no longer limited in the total time, as long as the data keeps coming
+type idleBody struct {
+	io.ReadCloser
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	timer  *time.Timer
+	idle   time.Duration
+}
+
+func (b *idleBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if b.timer != nil {
+		b.timer.Reset(b.idle)
+	}
+	if err != nil && err != io.EOF && b.ctx.Err() != nil {
+		err = context.Cause(b.ctx)
+	}
+	return n, err
+}
+
+func (b *idleBody) Close() error {
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+	b.cancel(nil)
+	return b.ReadCloser.Close()
+}
+
+// send the request with the stream client, the request fails once nothing arrives for the idle timeout
+func doDownload(req *http.Request) (*http.Response, error) {
+	idle := Client().Timeout
+	ctx, cancel := context.WithCancelCause(req.Context())
+	var timer *time.Timer
+	if idle > 0 {
+		timer = time.AfterFunc(idle, func() { cancel(fmt.Errorf("%w: %v", ErrIdleTimeout, idle)) })
+	}
+
+	resp, err := StreamClient().Do(req.WithContext(ctx))
+	if err != nil {
+		if timer != nil {
+			timer.Stop()
+		}
+		cancel(nil)
+		if cause := context.Cause(ctx); cause != nil && cause != context.Canceled {
+			return nil, cause
+		}
+		return nil, err
+	}
+	resp.Body = &idleBody{resp.Body, ctx, cancel, timer, idle}
+	return resp, nil
+}
+
+// read the whole body like ReadAll, with the idle timeout in place of the total timeout
+func DownloadAll(ctx context.Context, rawURL string, header http.Header) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	resp, err := doDownload(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || 300 <= resp.StatusCode {
+		return nil, newStatusError(rawURL, resp)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+const progressInterval = 250 * time.Millisecond
+
+type Progress struct {
+	Received int64   //bytes in the file so far, including the resumed ones
+	Total    int64   //-1 if the server doesn't tell
+	Speed    float64 //bytes per second since the last report
+}
+
+/*
+Download the url into the file, a partial file is continued with a range request.
+The progress is reported periodically and once more when the download completes.
+*/
+func DownloadFile(ctx context.Context, rawURL string, header http.Header, path string, onProgress func(Progress)) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0666)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	offset, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%v-", offset))
+	}
+	resp, err := doDownload(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent:
+		//continue from the offset
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		//the partial file is already complete
+		onProgress(Progress{Received: offset, Total: offset})
+		return nil
+	case resp.StatusCode == http.StatusOK:
+		//the server ignores the range, start over
+		if err := file.Truncate(0); err != nil {
+			return err
+		}
+		if offset, err = file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+	default:
+		return newStatusError(rawURL, resp)
+	}
+
+	total := int64(-1)
+	if resp.ContentLength >= 0 {
+		total = offset + resp.ContentLength
+	}
+	return copyWithProgress(file, resp.Body, offset, total, onProgress)
+}
+
+func copyWithProgress(dst io.Writer, src io.Reader, received int64, total int64, onProgress func(Progress)) error {
+	buffer := make([]byte, 32*1024)
+	lastTime := time.Now()
+	lastReceived := received
+	for {
+		n, err := src.Read(buffer)
+		if n > 0 {
+			if _, err := dst.Write(buffer[:n]); err != nil {
+				return err
+			}
+			received += int64(n)
+		}
+
+		if elapsed := time.Since(lastTime); elapsed >= progressInterval || err != nil {
+			onProgress(Progress{Received: received, Total: total, Speed: float64(received-lastReceived) / elapsed.Seconds()})
+			lastTime = time.Now()
+			lastReceived = received
+		}
+
+		if err == io.EOF {
+			if total != -1 && received != total {
+				return fmt.Errorf("download ended at %v of %v bytes", received, total)
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package httpclient_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"meowyplayer.com/utility/network/httpclient"
+)
+
+var content = bytes.Repeat([]byte("meowy"), 100000)
+
+func newContentServer(t *testing.T, ranges *[]string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*ranges = append(*ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "music.mp3", time.Time{}, bytes.NewReader(content))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDownloadFile(t *testing.T) {
+	ranges := []string{}
+	server := newContentServer(t, &ranges)
+	path := filepath.Join(t.TempDir(), "music.part")
+
+	var last httpclient.Progress
+	if err := httpclient.DownloadFile(context.Background(), server.URL, nil, path, func(p httpclient.Progress) { last = p }); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if !bytes.Equal(data, content) {
+		t.Errorf("downloaded %v of %v bytes\n", len(data), len(content))
+	}
+	if last.Received != int64(len(content)) || last.Total != int64(len(content)) {
+		t.Errorf("unexpected progress: %+v\n", last)
+	}
+	if ranges[0] != "" {
+		t.Errorf("unexpected range request: %v\n", ranges[0])
+	}
+}
+
+func TestDownloadFileResume(t *testing.T) {
+	ranges := []string{}
+	server := newContentServer(t, &ranges)
+	path := filepath.Join(t.TempDir(), "music.part")
+	if err := os.WriteFile(path, content[:1234], 0666); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if err := httpclient.DownloadFile(context.Background(), server.URL, nil, path, func(httpclient.Progress) {}); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if !bytes.Equal(data, content) {
+		t.Errorf("resumed file differs from the content\n")
+	}
+	if ranges[0] != "bytes=1234-" {
+		t.Errorf("unexpected range request: %v\n", ranges[0])
+	}
+}
+
+func TestDownloadFileComplete(t *testing.T) {
+	ranges := []string{}
+	server := newContentServer(t, &ranges)
+	path := filepath.Join(t.TempDir(), "music.part")
+	if err := os.WriteFile(path, content, 0666); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if err := httpclient.DownloadFile(context.Background(), server.URL, nil, path, func(httpclient.Progress) {}); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() != int64(len(content)) {
+		t.Errorf("complete file is modified\n")
+	}
+}
+
+func TestDownloadFileStatusError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	err := httpclient.DownloadFile(context.Background(), server.URL, nil, filepath.Join(t.TempDir(), "music.part"), func(httpclient.Progress) {})
+	statusErr := &httpclient.StatusError{}
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Errorf("expected a not found status error, got %v\n", err)
+	}
+}
+
+// send the content in pieces, pausing between them, then stall if asked
+func newSlowServer(t *testing.T, pieces int, pause time.Duration, stall bool) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i < pieces; i++ {
+			w.Write([]byte("meowy"))
+			w.(http.Flusher).Flush()
+			time.Sleep(pause)
+		}
+		if stall {
+			<-r.Context().Done()
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func configureTimeout(t *testing.T, timeout time.Duration) {
+	config := httpclient.DefaultConfig()
+	config.Timeout = timeout
+	if err := httpclient.Configure(config); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	t.Cleanup(func() { httpclient.Configure(httpclient.DefaultConfig()) })
+}
+
+func TestDownloadSlowerThanTimeout(t *testing.T) {
+	configureTimeout(t, 100*time.Millisecond)
+	server := newSlowServer(t, 10, 40*time.Millisecond, false)
+
+	//the whole body takes 400ms, but a piece arrives well within the timeout
+	data, err := httpclient.DownloadAll(context.Background(), server.URL, nil)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if string(data) != strings.Repeat("meowy", 10) {
+		t.Errorf("unexpected content: %q\n", data)
+	}
+
+	path := filepath.Join(t.TempDir(), "music.part")
+	if err := httpclient.DownloadFile(context.Background(), server.URL, nil, path, func(httpclient.Progress) {}); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() != 50 {
+		t.Errorf("expected the complete file, got %v %v\n", info, err)
+	}
+}
+
+func TestDownloadIdleTimeout(t *testing.T) {
+	configureTimeout(t, 100*time.Millisecond)
+	server := newSlowServer(t, 2, 10*time.Millisecond, true)
+
+	if _, err := httpclient.DownloadAll(context.Background(), server.URL, nil); !errors.Is(err, httpclient.ErrIdleTimeout) {
+		t.Errorf("expected %v, got %v\n", httpclient.ErrIdleTimeout, err)
+	}
+	path := filepath.Join(t.TempDir(), "music.part")
+	if err := httpclient.DownloadFile(context.Background(), server.URL, nil, path, func(httpclient.Progress) {}); !errors.Is(err, httpclient.ErrIdleTimeout) {
+		t.Errorf("expected %v, got %v\n", httpclient.ErrIdleTimeout, err)
+	}
+}
@@ -0,0 +1,25 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// nothing arrives from the server for the idle timeout
+var ErrIdleTimeout = errors.New("the server stopped sending data")
+
+// the server answered with a status other than 2xx
+type StatusError struct {
+	URL        string
+	StatusCode int
+	Status     string
+}
+
+func newStatusError(rawURL string, resp *http.Response) *StatusError {
+	return &StatusError{URL: rawURL, StatusCode: resp.StatusCode, Status: resp.Status}
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("failed to fetch %v: %v", e.URL, e.Status)
+}
@@ -0,0 +1,74 @@
+package radio
+
+import (
+	"io"
+	"strings"
+)
+
+/*
+Read the audio of an ICY stream, the metadata blocks sent every interval bytes are taken out.
+A block is one byte of its length in 16 bytes, followed by the text padded with zeros, e.g.
+"StreamTitle='Artist - Title';StreamUrl='';"
+https://cast.readme.io/docs/icy
+*/
+type MetadataReader struct {
+	r         io.Reader
+	interval  int
+	remaining int //audio bytes left before the next block
+	onTitle   func(string)
+}
+
+func NewMetadataReader(r io.Reader, interval int, onTitle func(string)) *MetadataReader {
+	return &MetadataReader{r: r, interval: interval, remaining: interval, onTitle: onTitle}
+}
+
+func (m *MetadataReader) Read(p []byte) (int, error) {
+	if m.remaining == 0 {
+		if err := m.readMetadata(); err != nil {
+			return 0, err
+		}
+		m.remaining = m.interval
+	}
+
+	n, err := m.r.Read(p[:min(len(p), m.remaining)])
+	m.remaining -= n
+	return n, err
+}
+
+// the empty blocks mean the title hasn't changed
+func (m *MetadataReader) readMetadata() error {
+	length := []byte{0}
+	if _, err := io.ReadFull(m.r, length); err != nil {
+		return err
+	}
+	if length[0] == 0 {
+		return nil
+	}
+
+	block := make([]byte, int(length[0])*16)
+	if _, err := io.ReadFull(m.r, block); err != nil {
+		return err
+	}
+	if title, ok := parseStreamTitle(strings.TrimRight(string(block), "\x00")); ok {
+		m.onTitle(title)
+	}
+	return nil
+}
+
+// the title is quoted, and may itself contain quotes
+func parseStreamTitle(metadata string) (string, bool) {
+	const key = "StreamTitle='"
+	start := strings.Index(metadata, key)
+	if start == -1 {
+		return "", false
+	}
+	value := metadata[start+len(key):]
+	end := strings.Index(value, "';")
+	if end == -1 {
+		end = strings.LastIndex(value, "'")
+	}
+	if end == -1 {
+		return "", false
+	}
+	return strings.TrimSpace(value[:end]), true
+}
@@ -0,0 +1,47 @@
+package radio_test
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"testing"
+
+	"meowyplayer.com/utility/network/radio"
+)
+
+// a metadata block padded to the multiple of 16 bytes
+func metadataBlock(text string) []byte {
+	length := (len(text) + 15) / 16
+	block := append([]byte{byte(length)}, text...)
+	return append(block, make([]byte, length*16-len(text))...)
+}
+
+func TestMetadataReader(t *testing.T) {
+	stream := bytes.Buffer{}
+	stream.WriteString("abcd")
+	stream.Write(metadataBlock("StreamTitle='Meowy - Renai Circulation';StreamUrl='';"))
+	stream.WriteString("efgh")
+	stream.Write(metadataBlock(""))
+	stream.WriteString("ijkl")
+	stream.Write(metadataBlock("StreamTitle='It's Meowy';"))
+	stream.WriteString("mn")
+
+	titles := []string{}
+	audio, err := io.ReadAll(radio.NewMetadataReader(&stream, 4, func(title string) { titles = append(titles, title) }))
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if string(audio) != "abcdefghijklmn" {
+		t.Errorf("unexpected audio: %q\n", audio)
+	}
+	if !slices.Equal(titles, []string{"Meowy - Renai Circulation", "It's Meowy"}) {
+		t.Errorf("unexpected titles: %q\n", titles)
+	}
+}
+
+func TestMetadataReaderTruncated(t *testing.T) {
+	stream := bytes.NewReader(append([]byte("abcd"), 2, 'S'))
+	if _, err := io.ReadAll(radio.NewMetadataReader(stream, 4, func(string) {})); err == nil {
+		t.Errorf("expected the truncated metadata to fail\n")
+	}
+}
@@ -0,0 +1,191 @@
+package radio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"meowyplayer.com/utility/logger"
+	"meowyplayer.com/utility/network/httpclient"
+)
+
+var networkLog = logger.Subsystem(logger.Network)
+
+var ErrUnsupportedFormat = errors.New("the stream is not mp3")
+
+const chunkSize = 8 * 1024
+
+type Options struct {
+	Buffer     int           //chunks of audio fetched ahead of the playback
+	Reconnects int           //failed reconnects in a row before giving up
+	Backoff    time.Duration //the wait before the first reconnect, doubled after every failure
+}
+
+// about half a minute of a 128 kbps stream is buffered
+func DefaultOptions() Options {
+	return Options{Buffer: 64, Reconnects: 5, Backoff: time.Second}
+}
+
+/*
+An endless mp3 stream, such as an Icecast or SHOUTcast station.
+The audio is fetched ahead in the background, so that the playback goes on through the network hiccups,
+and the connection is opened again whenever it drops.
+*/
+type Stream struct {
+	Name    string //the station name told by the server, empty if unknown
+	url     string
+	options Options
+	onTitle func(string)
+	chunks  chan []byte
+	chunk   []byte //the unread rest of the current chunk
+	cancel  context.CancelFunc
+	done    chan struct{}
+	err     error //why the fetching stopped, set before done is closed
+}
+
+// connect to the stream, the titles announced by the station are passed to onTitle
+func Open(ctx context.Context, rawURL string, options Options, onTitle func(string)) (*Stream, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	s := &Stream{
+		url:     rawURL,
+		options: options,
+		onTitle: onTitle,
+		chunks:  make(chan []byte, options.Buffer),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	resp, err := s.connect(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	s.Name = resp.Header.Get("icy-name")
+	go s.fetch(ctx, resp)
+	return s, nil
+}
+
+// the metadata is asked for, the stream client has no total timeout
+func (s *Stream) connect(ctx context.Context) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Icy-MetaData", "1")
+	resp, err := httpclient.StreamClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || 300 <= resp.StatusCode {
+		resp.Body.Close()
+		return nil, &httpclient.StatusError{URL: s.url, StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if !slices.Contains([]string{"audio/mpeg", "audio/mp3", "audio/mpeg3"}, mediaType) {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedFormat, resp.Header.Get("Content-Type"))
+	}
+	return resp, nil
+}
+
+func (s *Stream) fetch(ctx context.Context, resp *http.Response) {
+	defer close(s.done)
+	defer close(s.chunks)
+
+	failures := 0
+	for {
+		received, err := s.copyChunks(ctx, resp)
+		resp.Body.Close()
+		if ctx.Err() != nil {
+			s.err = ctx.Err()
+			return
+		}
+		if received > 0 {
+			failures = 0
+		}
+
+		//a station never ends, even the end of the response is a dropped connection
+		for backoff := s.options.Backoff << failures; ; backoff *= 2 {
+			if failures >= s.options.Reconnects {
+				s.err = fmt.Errorf("lost the stream %v: %w", s.url, err)
+				return
+			}
+			failures++
+			networkLog.Warn("reconnecting to the stream", "url", s.url, "backoff", backoff, "err", err)
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				s.err = ctx.Err()
+				return
+			}
+			if resp, err = s.connect(ctx); err == nil {
+				break
+			}
+		}
+	}
+}
+
+// pass the audio on in chunks until the connection fails, return the bytes passed on
+func (s *Stream) copyChunks(ctx context.Context, resp *http.Response) (int64, error) {
+	audio := io.Reader(resp.Body)
+	if interval, err := strconv.Atoi(resp.Header.Get("icy-metaint")); err == nil && interval > 0 {
+		audio = NewMetadataReader(resp.Body, interval, s.onTitle)
+	}
+
+	received := int64(0)
+	for {
+		chunk := make([]byte, chunkSize)
+		n, err := audio.Read(chunk)
+		if n > 0 {
+			select {
+			case s.chunks <- chunk[:n]:
+				received += int64(n)
+			case <-ctx.Done():
+				return received, ctx.Err()
+			}
+		}
+		if err != nil {
+			return received, err
+		}
+	}
+}
+
+// block until the audio arrives, the error is returned once the buffered audio runs out
+func (s *Stream) Read(p []byte) (int, error) {
+	if len(s.chunk) == 0 {
+		chunk, ok := <-s.chunks
+		if !ok {
+			return 0, s.err
+		}
+		s.chunk = chunk
+	}
+	n := copy(p, s.chunk)
+	s.chunk = s.chunk[n:]
+	return n, nil
+}
+
+// closed once the stream stops fetching, either closed or given up on
+func (s *Stream) Done() <-chan struct{} {
+	return s.done
+}
+
+// why the stream stopped, nil while it is still fetching
+func (s *Stream) Err() error {
+	select {
+	case <-s.done:
+		return s.err
+	default:
+		return nil
+	}
+}
+
+func (s *Stream) Close() error {
+	s.cancel()
+	return nil
+}
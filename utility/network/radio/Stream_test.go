@@ -0,0 +1,121 @@
+package radio_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"meowyplayer.com/utility/network/httpclient"
+	"meowyplayer.com/utility/network/radio"
+)
+
+var testOptions = radio.Options{Buffer: 4, Reconnects: 2, Backoff: time.Millisecond}
+
+// every connection sends the next part of the audio, with a title announced after the first 4 bytes
+func newStationServer(t *testing.T, parts []string) (*httptest.Server, *atomic.Int64) {
+	connections := atomic.Int64{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		index := int(connections.Add(1)) - 1
+		if index >= len(parts) {
+			http.Error(w, "off air", http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("Icy-MetaData") != "1" {
+			t.Errorf("expected the metadata to be asked for\n")
+		}
+		w.Header().Set("Content-Type", "audio/mpeg")
+		w.Header().Set("icy-name", "Meowy FM")
+		w.Header().Set("icy-metaint", "4")
+		part := parts[index]
+		w.Write([]byte(part[:4]))
+		w.Write(metadataBlock("StreamTitle='Part " + string(rune('1'+index)) + "';"))
+		w.Write([]byte(part[4:]))
+	}))
+	t.Cleanup(server.Close)
+	return server, &connections
+}
+
+func TestStreamReconnect(t *testing.T) {
+	server, connections := newStationServer(t, []string{"abcd", "efghij"})
+
+	lock := sync.Mutex{}
+	titles := []string{}
+	stream, err := radio.Open(context.Background(), server.URL, testOptions, func(title string) {
+		lock.Lock()
+		defer lock.Unlock()
+		titles = append(titles, title)
+	})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	defer stream.Close()
+	if stream.Name != "Meowy FM" {
+		t.Errorf("unexpected name: %v\n", stream.Name)
+	}
+
+	//the audio goes on across the dropped connection, until the server is off air
+	audio, err := io.ReadAll(stream)
+	statusErr := &httpclient.StatusError{}
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected the stream to be lost, got %v\n", err)
+	}
+	if string(audio) != "abcdefghij" {
+		t.Errorf("unexpected audio: %q\n", audio)
+	}
+	if connections.Load() != 2+int64(testOptions.Reconnects) {
+		t.Errorf("expected %v connections, got %v\n", 2+testOptions.Reconnects, connections.Load())
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	if strings.Join(titles, ",") != "Part 1,Part 2" {
+		t.Errorf("unexpected titles: %q\n", titles)
+	}
+	<-stream.Done()
+	if stream.Err() == nil {
+		t.Errorf("expected the error to be kept\n")
+	}
+}
+
+func TestStreamClose(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "audio/mpeg")
+		w.Write([]byte("abcd"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	stream, err := radio.Open(context.Background(), server.URL, testOptions, func(string) {})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	buffer := make([]byte, 4)
+	if _, err := io.ReadFull(stream, buffer); err != nil || string(buffer) != "abcd" {
+		t.Fatalf("unexpected audio %q: %v\n", buffer, err)
+	}
+
+	stream.Close()
+	if _, err := io.ReadAll(stream); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the closed stream to be cancelled, got %v\n", err)
+	}
+}
+
+func TestStreamUnsupportedFormat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html></html>"))
+	}))
+	defer server.Close()
+
+	if _, err := radio.Open(context.Background(), server.URL, testOptions, func(string) {}); !errors.Is(err, radio.ErrUnsupportedFormat) {
+		t.Errorf("expected %v, got %v\n", radio.ErrUnsupportedFormat, err)
+	}
+}
@@ -0,0 +1,142 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"meowyplayer.com/utility/network/fileformat"
+	"meowyplayer.com/utility/network/httpclient"
+)
+
+const BiliBiliHost = `https://api.bilibili.com`
+const bilibiliVideoHost = `https://www.bilibili.com/video/`
+
+// https://app.quicktype.io/
+type bilibiliSearchResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    struct {
+		Result []bilibiliSearchResult `json:"result"`
+	} `json:"data"`
+}
+
+type bilibiliSearchResult struct {
+	Type        string `json:"type"`
+	Bvid        string `json:"bvid"`
+	Mid         int64  `json:"mid"`
+	Author      string `json:"author"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Pic         string `json:"pic"`
+	Play        int64  `json:"play"`
+	Duration    string `json:"duration"`
+	Pubdate     int64  `json:"pubdate"`
+}
+
+type BiliBiliScraper struct {
+	host     string
+	tagRegex *regexp.Regexp
+}
+
+func NewBiliBiliScraper(host string) *BiliBiliScraper {
+	//search keywords are highlighted by <em class="keyword"> in the titles
+	return &BiliBiliScraper{host, regexp.MustCompile(`<[^>]*>`)}
+}
+
+// the sort order is sent to BiliBili, its duration buckets differ from ours so the filters are applied here
+func (s *BiliBiliScraper) Search(ctx context.Context, title string, options SearchOptions) ([]fileformat.VideoResult, error) {
+	content, err := s.getContent(ctx, title, &options)
+	if err != nil {
+		return nil, err
+	}
+	results, err := s.scrapeContent(content)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(results, func(r fileformat.VideoResult) bool { return !options.Match(&r) }), nil
+}
+
+func (s *BiliBiliScraper) getContent(ctx context.Context, title string, options *SearchOptions) ([]bilibiliSearchResult, error) {
+	order := map[SortOrder]string{SortByRelevance: "totalrank", SortByViews: "click", SortByDate: "pubdate"}[options.Sort]
+	query := url.Values{"search_type": {"video"}, "keyword": {title}, "order": {order}, "page": {strconv.Itoa(options.page())}}
+	searchURL := s.host + `/x/web-interface/search/type?` + query.Encode()
+	networkLog.Info("scraping BiliBili", "url", searchURL)
+	resp, err := httpclient.Get(ctx, searchURL, http.Header{"Referer": {"https://www.bilibili.com"}})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	searchResp := bilibiliSearchResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&searchResp); err != nil {
+		return nil, err
+	}
+	if searchResp.Code != 0 {
+		return nil, fmt.Errorf("BiliBili search failed (%v): %v", searchResp.Code, searchResp.Message)
+	}
+	return searchResp.Data.Result, nil
+}
+
+func (s *BiliBiliScraper) scrapeContent(content []bilibiliSearchResult) ([]fileformat.VideoResult, error) {
+	networkLog.Debug("scraping results", "count", len(content))
+
+	//parse into the results, the broken ones are skipped
+	results := []fileformat.VideoResult{}
+	parseErrs := []error{}
+	for i := range content {
+		result, err := s.parseResult(&content[i])
+		if err != nil {
+			parseErrs = append(parseErrs, &ParseError{Site: "BiliBili", Index: i, Err: err})
+			continue
+		}
+		results = append(results, result)
+	}
+
+	//nothing can be read from the response, most likely the api has changed
+	if len(results) == 0 && len(parseErrs) > 0 {
+		return nil, fmt.Errorf("%w: %v results found on BiliBili: %w", ErrLayoutChanged, len(content), errors.Join(parseErrs...))
+	}
+	for _, err := range parseErrs {
+		networkLog.Warn("skipped a result", "err", err)
+	}
+
+	networkLog.Debug("scraping completed")
+	return results, nil
+}
+
+func (s *BiliBiliScraper) parseResult(result *bilibiliSearchResult) (fileformat.VideoResult, error) {
+	//the thumbnail url comes without scheme
+	thumbnailURL := result.Pic
+	if strings.HasPrefix(thumbnailURL, "//") {
+		thumbnailURL = "https:" + thumbnailURL
+	}
+
+	length, err := parseDuration(result.Duration)
+	if err != nil {
+		return fileformat.VideoResult{}, &FieldError{"length", err}
+	}
+
+	return fileformat.VideoResult{
+		VideoID:      result.Bvid,
+		URL:          bilibiliVideoHost + result.Bvid,
+		ThumbnailURL: thumbnailURL,
+		Length:       length,
+		Views:        result.Play,
+		Uploaded:     time.Unix(result.Pubdate, 0),
+		Title:        html.UnescapeString(s.tagRegex.ReplaceAllString(result.Title, "")),
+		ChannelID:    strconv.FormatInt(result.Mid, 10),
+		ChannelTitle: html.UnescapeString(result.Author),
+		Stats:        fmt.Sprintf("%v plays | %v", result.Play, time.Unix(result.Pubdate, 0).Format(time.DateOnly)),
+		Description:  html.UnescapeString(result.Description),
+	}, nil
+}
@@ -0,0 +1,124 @@
+package scraper_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"meowyplayer.com/utility/network/scraper"
+)
+
+// replay the recorded search response, with the thumbnails pointing back to the local server
+func newBiliBiliServer(t *testing.T) *httptest.Server {
+	search, err := os.ReadFile("testdata/bilibili_search.json")
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/x/web-interface/search/type" && r.URL.Query().Get("order") != "totalrank" && r.URL.Query().Get("order") != "pubdate":
+			w.Write([]byte(`{"code":-400,"message":"invalid order"}`))
+		case r.URL.Path == "/x/web-interface/search/type" && r.URL.Query().Get("keyword") == "renai circulation" && r.URL.Query().Get("page") != "1":
+			w.Write([]byte(`{"code":0,"message":"0","data":{"page":2,"result":[]}}`))
+		case r.URL.Path == "/x/web-interface/search/type" && r.URL.Query().Get("keyword") == "broken":
+			w.Write([]byte(`{"code":0,"message":"0","data":{"page":1,"result":[{"bvid":"BV1","title":"broken","duration":"forever"}]}}`))
+		case r.URL.Path == "/x/web-interface/search/type" && r.URL.Query().Get("keyword") == "renai circulation":
+			w.Write([]byte(strings.ReplaceAll(string(search), "{{host}}", server.URL)))
+		case r.URL.Path == "/x/web-interface/search/type":
+			w.Write([]byte(`{"code":-412,"message":"request was banned"}`))
+		case strings.HasPrefix(r.URL.Path, "/bfs/archive/"):
+			w.Write([]byte("thumbnail"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestBiliBiliSearch(t *testing.T) {
+	server := newBiliBiliServer(t)
+	results, err := scraper.NewBiliBiliScraper(server.URL).Search(context.Background(), "renai circulation", scraper.SearchOptions{Page: 1})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %v\n", len(results))
+	}
+
+	result := results[0]
+	if result.VideoID != "BV17x411w7KC" || result.ChannelID != "1234" || result.ChannelTitle != "MeowyUp" {
+		t.Errorf("unexpected result: %+v\n", result)
+	}
+	if result.Title != "Renai Circulation & more" {
+		t.Errorf("unexpected title: %v\n", result.Title)
+	}
+	if result.Length != 4*time.Minute+15*time.Second {
+		t.Errorf("unexpected length: %v\n", result.Length)
+	}
+	if result.Stats != "98765 plays | "+time.Unix(1672617600, 0).Format(time.DateOnly) {
+		t.Errorf("unexpected stats: %v\n", result.Stats)
+	}
+	if result.Views != 98765 || !result.Uploaded.Equal(time.Unix(1672617600, 0)) {
+		t.Errorf("unexpected views or upload time: %+v\n", result)
+	}
+	if result.URL != "https://www.bilibili.com/video/BV17x411w7KC" {
+		t.Errorf("unexpected url: %v\n", result.URL)
+	}
+	if result.ThumbnailURL != server.URL+"/bfs/archive/renai.jpg" {
+		t.Errorf("unexpected thumbnail: %v\n", result.ThumbnailURL)
+	}
+
+	if results[1].Length != time.Hour+2*time.Minute+3*time.Second || results[1].ChannelTitle != "Meowy & Co" {
+		t.Errorf("unexpected result: %+v\n", results[1])
+	}
+}
+
+func TestBiliBiliSearchOptions(t *testing.T) {
+	server := newBiliBiliServer(t)
+	options := scraper.SearchOptions{Page: 1, Sort: scraper.SortByDate, Duration: scraper.LongDuration, Channel: "co"}
+	results, err := scraper.NewBiliBiliScraper(server.URL).Search(context.Background(), "renai circulation", options)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if len(results) != 1 || results[0].VideoID != "BV1xx411c7mD" {
+		t.Errorf("expected only the long mix, got %+v\n", results)
+	}
+}
+
+func TestBiliBiliSearchLastPage(t *testing.T) {
+	server := newBiliBiliServer(t)
+	results, err := scraper.NewBiliBiliScraper(server.URL).Search(context.Background(), "renai circulation", scraper.SearchOptions{Page: 2})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected no results past the last page, got %v\n", len(results))
+	}
+}
+
+func TestBiliBiliSearchRejected(t *testing.T) {
+	server := newBiliBiliServer(t)
+	if _, err := scraper.NewBiliBiliScraper(server.URL).Search(context.Background(), "chicken nugget", scraper.SearchOptions{Page: 1}); err == nil {
+		t.Fatalf("expected an error for the rejected search\n")
+	}
+}
+
+func TestBiliBiliSearchBroken(t *testing.T) {
+	server := newBiliBiliServer(t)
+	_, err := scraper.NewBiliBiliScraper(server.URL).Search(context.Background(), "broken", scraper.SearchOptions{Page: 1})
+	if !errors.Is(err, scraper.ErrLayoutChanged) {
+		t.Fatalf("expected %v, got %v\n", scraper.ErrLayoutChanged, err)
+	}
+	parseErr := &scraper.ParseError{}
+	if !errors.As(err, &parseErr) || parseErr.Site != "BiliBili" || parseErr.Index != 0 {
+		t.Errorf("expected the broken result to be reported, got %v\n", err)
+	}
+}
@@ -1,128 +1,205 @@
 package scraper
 
 import (
-	"html"
-	"io"
-	"log"
-	"net/http"
+	"context"
+	"errors"
+	"fmt"
 	"net/url"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
-	"fyne.io/fyne/v2"
-	"meowyplayer.com/utility/assert"
+	"golang.org/x/net/html"
 	"meowyplayer.com/utility/network/fileformat"
+	"meowyplayer.com/utility/network/httpclient"
 )
 
+const ClipzagHost = `https://clipzag.com`
+
+/*
+Scrape the Clipzag search page by walking its DOM.
+The results are listed in <div class="videolist">, every result starts with an <a class="title-color"> holding the thumbnail, the length and the title,
+followed by <div class="viewsanduser"> with the channel and the stats, and <div class="postdiscription">.
+*/
 type ClipzagScraper struct {
-	regex *regexp.Regexp
+	host       string
+	statsRegex *regexp.Regexp
 }
 
-func NewClipzagScraper() *ClipzagScraper {
-	const pattern = `<a class="title-color" href="watch\?v=(.+)">\n` + //videoID
-		`<div class="video-thumbs">\n` +
-		`<img class="videosthumbs-style" data-thumb-m data-thumb="//(.+)" src="//.+"><span class="duration">(.+)</span></div>\n` + //thumbnail, length
-		`<div class="title-style" title="(.+)">.+</div>\n` + //title
-		`</a>\n` +
-		`<div class="viewsanduser">\n` +
-		`<span style="font-weight:bold;"><a class="by-user" href="/channel\?id=(.+)">(.+)</a><br/>(.+)</span>\n` + //channel id, channel title, stats
-		`</div>\n` +
-		`<div class="postdiscription">(.+)</div>` //description
-
-	regex, err := regexp.Compile(pattern)
-	assert.NoErr(err, "failed to compile Clipzag scraper regex")
-	return &ClipzagScraper{regex}
+func NewClipzagScraper(host string) *ClipzagScraper {
+	//the stats read like "12,345 views | 3 years ago"
+	statsRegex := regexp.MustCompile(`(?:([\d,]+) views?)?.*?(?:(\d+) (second|minute|hour|day|week|month|year)s? ago)?$`)
+	return &ClipzagScraper{host, statsRegex}
 }
 
-func (s *ClipzagScraper) Search(title string) ([]fileformat.VideoResult, error) {
-	content, err := s.getContent(title)
+// only the sort order is understood by Clipzag, the filters are applied to the scraped results
+func (s *ClipzagScraper) Search(ctx context.Context, title string, options SearchOptions) ([]fileformat.VideoResult, error) {
+	content, err := s.getContent(ctx, title, &options)
 	if err != nil {
 		return nil, err
 	}
-	return s.scrapeContent(content), nil
+	results, err := s.scrapeContent(content)
+	return slices.DeleteFunc(results, func(r fileformat.VideoResult) bool { return !options.Match(&r) }), err
 }
 
-func (s *ClipzagScraper) getContent(title string) (string, error) {
-	url := `https://clipzag.com/search?` + url.Values{"q": {title}, "order": {"relevance"}}.Encode()
-	log.Printf("scraping from %v\n", url)
-	resp, err := http.Get(url)
-	if err != nil {
-		return "", err
+func (s *ClipzagScraper) getContent(ctx context.Context, title string, options *SearchOptions) (string, error) {
+	order := map[SortOrder]string{SortByRelevance: "relevance", SortByViews: "viewCount", SortByDate: "date"}[options.Sort]
+	query := url.Values{"q": {title}, "order": {order}}
+	if options.page() > 1 {
+		query.Set("page", strconv.Itoa(options.page()))
 	}
-	defer resp.Body.Close()
-
-	data, err := io.ReadAll(resp.Body)
+	url := s.host + `/search?` + query.Encode()
+	networkLog.Info("scraping Clipzag", "url", url)
+	data, err := httpclient.ReadAll(ctx, url, nil)
 	return string(data), err
 }
 
-func (s *ClipzagScraper) scrapeContent(content string) []fileformat.VideoResult {
-	//parse regex and prepare output buffers
-	matches := s.regex.FindAllStringSubmatch(content, -1)
-	results := make([]fileformat.VideoResult, len(matches))
-	log.Printf("scraping %v results...\n", len(matches))
-
-	//parse into the results
-	wg := sync.WaitGroup{}
-	wg.Add(len(matches))
-	for i, match := range matches {
-		i := i
-		match := match
-		go func() {
-			defer wg.Done()
-			s.parseMatch(match, &results[i])
-		}()
-	}
-	wg.Wait()
-
-	log.Println("scraping completed")
-	return results
-
-	//This code doesn't work well, since the output of the channel is not in order
-	//This can cause unwanted result appear on the top of the search results
-	/*
-		//parse matches into results concurrently
-		matches := s.regex.FindAllStringSubmatch(content, -1)
-		log.Printf("scraping %v results...\n", len(matches))
-		resultChan := make(chan YoutubeResult, len(matches))
-		for _, match := range matches {
-			go s.parseMatch(match, resultChan)
+func (s *ClipzagScraper) scrapeContent(content string) ([]fileformat.VideoResult, error) {
+	document, err := html.Parse(strings.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	//the list is there even when nothing is found, without it the layout has changed
+	videoList := findFirst(document, func(n *html.Node) bool { return n.Data == "div" && hasClass(n, "videolist") })
+	if videoList == nil {
+		return nil, fmt.Errorf("%w: no result list found on Clipzag", ErrLayoutChanged)
+	}
+
+	//parse every result, the broken ones are skipped
+	anchors := findAll(videoList, func(n *html.Node) bool { return n.Data == "a" && hasClass(n, "title-color") })
+	results := []fileformat.VideoResult{}
+	parseErrs := []error{}
+	for i, anchor := range anchors {
+		result, err := s.parseResult(anchor)
+		if err != nil {
+			parseErrs = append(parseErrs, &ParseError{Site: "Clipzag", Index: i, Err: err})
+			continue
 		}
+		results = append(results, result)
+	}
+	networkLog.Debug("scraping results", "count", len(results))
+
+	//none of the results can be read, most likely the layout has changed
+	if len(results) == 0 && len(parseErrs) > 0 {
+		return nil, fmt.Errorf("%w: %v results found on Clipzag: %w", ErrLayoutChanged, len(anchors), errors.Join(parseErrs...))
+	}
+	for _, err := range parseErrs {
+		networkLog.Warn("skipped a result", "err", err)
+	}
+
+	networkLog.Debug("scraping completed")
+	return results, nil
+}
+
+func (s *ClipzagScraper) parseResult(anchor *html.Node) (fileformat.VideoResult, error) {
+	result := fileformat.VideoResult{}
+
+	//the anchor links to "watch?v=<video id>"
+	link, err := url.Parse(attribute(anchor, "href"))
+	if err != nil || link.Query().Get("v") == "" {
+		return result, &FieldError{"video id", fmt.Errorf("invalid link %q", attribute(anchor, "href"))}
+	}
+	result.VideoID = link.Query().Get("v")
+	result.URL = youtubeVideoURL(result.VideoID)
+
+	thumbnail := findFirst(anchor, func(n *html.Node) bool { return n.Data == "img" && attribute(n, "data-thumb") != "" })
+	if thumbnail == nil {
+		return result, &FieldError{"thumbnail", errMissingElement}
+	}
+	if result.ThumbnailURL, err = s.resolveURL(attribute(thumbnail, "data-thumb")); err != nil {
+		return result, &FieldError{"thumbnail", err}
+	}
 
-		//collect results
-		results := make([]YoutubeResult, 0, len(matches))
-		for range matches {
-			results = append(results, <-resultChan)
+	duration := findFirst(anchor, func(n *html.Node) bool { return n.Data == "span" && hasClass(n, "duration") })
+	if duration == nil {
+		return result, &FieldError{"length", errMissingElement}
+	}
+	if result.Length, err = parseDuration(textContent(duration)); err != nil {
+		return result, &FieldError{"length", err}
+	}
+
+	title := findFirst(anchor, func(n *html.Node) bool { return n.Data == "div" && hasClass(n, "title-style") })
+	if title == nil {
+		return result, &FieldError{"title", errMissingElement}
+	}
+	result.Title = attribute(title, "title")
+	if result.Title == "" {
+		result.Title = textContent(title)
+	}
+
+	//the channel and the description follow the anchor
+	viewsAndUser := nextElement(anchor, func(n *html.Node) bool { return hasClass(n, "viewsanduser") })
+	if viewsAndUser == nil {
+		return result, &FieldError{"channel", errMissingElement}
+	}
+	channel := findFirst(viewsAndUser, func(n *html.Node) bool { return n.Data == "a" && hasClass(n, "by-user") })
+	if channel == nil {
+		return result, &FieldError{"channel", errMissingElement}
+	}
+	channelLink, err := url.Parse(attribute(channel, "href"))
+	if err != nil {
+		return result, &FieldError{"channel", err}
+	}
+	result.ChannelID = channelLink.Query().Get("id")
+	result.ChannelTitle = textContent(channel)
+
+	//the stats are the text after the channel link
+	stats := []string{}
+	for n := channel.NextSibling; n != nil; n = n.NextSibling {
+		if text := textContent(n); text != "" {
+			stats = append(stats, text)
 		}
+	}
+	result.Stats = strings.Join(stats, " ")
+	result.Views, result.Uploaded = s.parseStats(result.Stats, time.Now())
 
-		return results
-	*/
+	//some results have no description
+	if description := nextElement(anchor, func(n *html.Node) bool { return hasClass(n, "postdiscription") }); description != nil {
+		result.Description = textContent(description)
+	}
+	return result, nil
+}
+
+// the stats are only displayed, the views and the upload time are left zero if they can't be read
+func (s *ClipzagScraper) parseStats(stats string, now time.Time) (int64, time.Time) {
+	match := s.statsRegex.FindStringSubmatch(stats)
+	views, _ := strconv.ParseInt(strings.ReplaceAll(match[1], ",", ""), 10, 64)
+	if match[2] == "" {
+		return views, time.Time{}
+	}
+
+	//the relative upload time is rounded by the site anyway
+	n, _ := strconv.Atoi(match[2])
+	switch match[3] {
+	case "second":
+		return views, now.Add(-time.Duration(n) * time.Second)
+	case "minute":
+		return views, now.Add(-time.Duration(n) * time.Minute)
+	case "hour":
+		return views, now.Add(-time.Duration(n) * time.Hour)
+	case "day":
+		return views, now.AddDate(0, 0, -n)
+	case "week":
+		return views, now.AddDate(0, 0, -7*n)
+	case "month":
+		return views, now.AddDate(0, -n, 0)
+	default:
+		return views, now.AddDate(-n, 0, 0)
+	}
 }
 
-func (s *ClipzagScraper) parseMatch(match []string, dst *fileformat.VideoResult) {
-	//download thumbnail
-	thumbnail, err := fyne.LoadResourceFromURLString(`https://` + match[2])
-	assert.NoErr(err, "failed to download the thumbnail")
-
-	//calculate video length
-	times := strings.Split(match[3], ":")
-	seconds := 0
-	for _, time := range times {
-		t, err := strconv.Atoi(time)
-		assert.NoErr(err, "invalid time conversion")
-		seconds = seconds*60 + t
-	}
-
-	*dst = fileformat.VideoResult{
-		VideoID:      match[1],
-		Thumbnail:    thumbnail,
-		Length:       time.Duration(seconds * int(time.Second)),
-		Title:        html.UnescapeString(match[4]),
-		ChannelID:    match[5],
-		ChannelTitle: html.UnescapeString(match[6]),
-		Stats:        html.UnescapeString(match[7]),
-		Description:  html.UnescapeString(match[8]),
+// the thumbnail urls come without scheme
+func (s *ClipzagScraper) resolveURL(rawURL string) (string, error) {
+	base, err := url.Parse(s.host)
+	if err != nil {
+		return "", err
+	}
+	reference, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
 	}
+	return base.ResolveReference(reference).String(), nil
 }
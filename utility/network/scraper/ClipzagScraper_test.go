@@ -1,29 +1,180 @@
 package scraper_test
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"meowyplayer.com/utility/network/fileformat"
 	"meowyplayer.com/utility/network/scraper"
 )
 
-//race condition detection, panic if occurs
-//go test -race -run NameOfThatTestFunc .
+// go test -run Clipzag -update . to regenerate the golden files from the saved pages
+var update = flag.Bool("update", false, "update the golden files")
 
-func TestChickenNugget(t *testing.T) {
-	SearchQuery(scraper.NewClipzagScraper(), "chicken nugget", t)
+// serve the saved page as the first page of the search result
+func newClipzagServer(t *testing.T, page string) *httptest.Server {
+	content, err := os.ReadFile(page)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/search" && r.URL.Query().Get("order") != "relevance" && r.URL.Query().Get("order") != "viewCount":
+			http.Error(w, "unexpected order", http.StatusBadRequest)
+		case r.URL.Path == "/search" && r.URL.Query().Get("page") != "":
+			w.Write([]byte(`<html><body><div class="videolist"></div></body></html>`))
+		case r.URL.Path == "/search":
+			w.Write([]byte(strings.ReplaceAll(string(content), "{{authority}}", server.Listener.Addr().String())))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
 }
 
-func TestMonogatari(t *testing.T) {
-	SearchQuery(scraper.NewClipzagScraper(), "renai circulation", t)
+func TestClipzagSearch(t *testing.T) {
+	const golden = "testdata/clipzag_search.golden.json"
+	server := newClipzagServer(t, "testdata/clipzag_search.html")
+	results, err := scraper.NewClipzagScraper(server.URL).Search(context.Background(), "renai circulation", scraper.SearchOptions{Page: 1})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	//the golden file doesn't depend on the server address, nor on the time the upload dates are relative to
+	uploaded := []time.Time{time.Now().AddDate(-3, 0, 0), time.Now().AddDate(0, 0, -1)}
+	for i := range results {
+		results[i].ThumbnailURL = strings.ReplaceAll(results[i].ThumbnailURL, server.Listener.Addr().String(), "{{authority}}")
+		if i < len(uploaded) && results[i].Uploaded.Sub(uploaded[i]).Abs() > time.Minute {
+			t.Errorf("unexpected upload time of %v: %v\n", results[i].VideoID, results[i].Uploaded)
+		}
+		results[i].Uploaded = time.Time{}
+	}
+
+	if *update {
+		data, err := json.MarshalIndent(results, "", "\t")
+		if err != nil {
+			t.Fatalf("%v\n", err)
+		}
+		if err := os.WriteFile(golden, data, 0666); err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}
+
+	data, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	expected := []fileformat.VideoResult{}
+	if err := json.Unmarshal(data, &expected); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("results differ from %v\ngot:  %+v\nwant: %+v\n", golden, results, expected)
+	}
 }
 
-func SearchQuery(scraper scraper.VideoScraper, title string, t *testing.T) {
-	results, err := scraper.Search(title)
+func TestClipzagSearchLastPage(t *testing.T) {
+	server := newClipzagServer(t, "testdata/clipzag_search.html")
+	results, err := scraper.NewClipzagScraper(server.URL).Search(context.Background(), "renai circulation", scraper.SearchOptions{Page: 2})
 	if err != nil {
 		t.Fatalf("%v\n", err)
 	}
+	if len(results) != 0 {
+		t.Errorf("expected no results past the last page, got %v\n", len(results))
+	}
+}
+
+// the site only sorts, the other options are filtered from the page
+func TestClipzagSearchOptions(t *testing.T) {
+	server := newClipzagServer(t, "testdata/clipzag_search.html")
+	tests := []struct {
+		options  scraper.SearchOptions
+		expected []string
+	}{
+		{scraper.SearchOptions{Sort: scraper.SortByViews}, []string{"auQxNYJ07Lc", "y2XArpEcygc"}},
+		{scraper.SearchOptions{Duration: scraper.LongDuration}, []string{"y2XArpEcygc"}},
+		{scraper.SearchOptions{Duration: scraper.ShortDuration}, []string{}},
+		{scraper.SearchOptions{UploadDate: scraper.ThisWeek}, []string{"y2XArpEcygc"}},
+		{scraper.SearchOptions{Channel: "meowy"}, []string{"auQxNYJ07Lc"}},
+		{scraper.SearchOptions{Channel: "UCexpress"}, []string{"y2XArpEcygc"}},
+	}
 
+	for _, test := range tests {
+		results, err := scraper.NewClipzagScraper(server.URL).Search(context.Background(), "renai circulation", test.options)
+		if err != nil {
+			t.Fatalf("%+v: %v\n", test.options, err)
+		}
+		ids := []string{}
+		for _, result := range results {
+			ids = append(ids, result.VideoID)
+		}
+		if !reflect.DeepEqual(ids, test.expected) {
+			t.Errorf("%+v: expected %v, got %v\n", test.options, test.expected, ids)
+		}
+	}
+}
+
+// the live stream has no length, it is skipped while the others are kept
+func TestClipzagSearchSkipsBrokenResult(t *testing.T) {
+	server := newClipzagServer(t, "testdata/clipzag_search.html")
+	results, err := scraper.NewClipzagScraper(server.URL).Search(context.Background(), "renai circulation", scraper.SearchOptions{Page: 1})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
 	for _, result := range results {
-		t.Logf("\n\n%+v\n\n", result)
+		if result.VideoID == "uYO7zbc-wJ0" {
+			t.Errorf("the result without length is not skipped\n")
+		}
+	}
+}
+
+func TestClipzagSearchLayoutChanged(t *testing.T) {
+	server := newClipzagServer(t, "testdata/clipzag_broken.html")
+	_, err := scraper.NewClipzagScraper(server.URL).Search(context.Background(), "renai circulation", scraper.SearchOptions{Page: 1})
+	if !errors.Is(err, scraper.ErrLayoutChanged) {
+		t.Fatalf("expected the layout changed error, got %v\n", err)
+	}
+}
+
+// a search without any result is not mistaken for a broken layout
+func TestClipzagSearchNoResults(t *testing.T) {
+	page := `<html><body><div class="videolist"></div></body></html>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(page)) }))
+	defer server.Close()
+
+	results, err := scraper.NewClipzagScraper(server.URL).Search(context.Background(), "renai circulation", scraper.SearchOptions{Page: 1})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected no results, got %v\n", len(results))
+	}
+}
+
+func TestClipzagSearchParseError(t *testing.T) {
+	page := `<div class="videolist"><a class="title-color" href="watch?v=auQxNYJ07Lc"><div class="title-style" title="Renai Circulation"></div></a></div>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(page)) }))
+	defer server.Close()
+
+	_, err := scraper.NewClipzagScraper(server.URL).Search(context.Background(), "renai circulation", scraper.SearchOptions{Page: 1})
+	parseErr := &scraper.ParseError{}
+	fieldErr := &scraper.FieldError{}
+	if !errors.Is(err, scraper.ErrLayoutChanged) || !errors.As(err, &parseErr) || !errors.As(err, &fieldErr) {
+		t.Fatalf("expected a structured parse error, got %v\n", err)
+	}
+	if parseErr.Index != 0 || fieldErr.Field != "thumbnail" {
+		t.Errorf("unexpected parse error: %v\n", err)
 	}
 }
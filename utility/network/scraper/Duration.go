@@ -0,0 +1,20 @@
+package scraper
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parse the "h:mm:ss" or "mm:ss" video length
+func parseDuration(text string) (time.Duration, error) {
+	seconds := 0
+	for _, field := range strings.Split(strings.TrimSpace(text), ":") {
+		t, err := strconv.Atoi(field)
+		if err != nil {
+			return 0, err
+		}
+		seconds = seconds*60 + t
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
@@ -0,0 +1,40 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+)
+
+// the page is fetched but nothing can be read from it, the scraper needs to be updated
+var ErrLayoutChanged = errors.New("the page layout has changed, the scraper is broken")
+
+var errMissingElement = errors.New("element not found")
+
+// a result that can't be read from the page
+type ParseError struct {
+	Site  string
+	Index int //the position of the result on the page
+	Err   error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%v result #%v: %v", e.Site, e.Index, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// a field of a result that can't be read
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Field, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
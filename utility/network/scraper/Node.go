@@ -0,0 +1,78 @@
+package scraper
+
+import (
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+func attribute(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+func hasClass(n *html.Node, class string) bool {
+	return n.Type == html.ElementNode && slices.Contains(strings.Fields(attribute(n, "class")), class)
+}
+
+// depth first search of the elements under the node, the node itself included
+func findFirst(n *html.Node, match func(*html.Node) bool) *html.Node {
+	if n.Type == html.ElementNode && match(n) {
+		return n
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if found := findFirst(child, match); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func findAll(n *html.Node, match func(*html.Node) bool) []*html.Node {
+	found := []*html.Node{}
+	if n.Type == html.ElementNode && match(n) {
+		found = append(found, n)
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		found = append(found, findAll(child, match)...)
+	}
+	return found
+}
+
+// the following sibling element that matches, stop at the next element of the same kind as the node
+func nextElement(n *html.Node, match func(*html.Node) bool) *html.Node {
+	for sibling := n.NextSibling; sibling != nil; sibling = sibling.NextSibling {
+		if sibling.Type != html.ElementNode {
+			continue
+		}
+		if match(sibling) {
+			return sibling
+		}
+		if sibling.Data == n.Data && attribute(sibling, "class") == attribute(n, "class") {
+			return nil
+		}
+	}
+	return nil
+}
+
+// the text under the node with the whitespaces collapsed, the entities are already decoded by the parser
+func textContent(n *html.Node) string {
+	builder := strings.Builder{}
+	var collect func(*html.Node)
+	collect = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			builder.WriteString(n.Data)
+			builder.WriteString(" ")
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			collect(child)
+		}
+	}
+	collect(n)
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
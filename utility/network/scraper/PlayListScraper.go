@@ -0,0 +1,11 @@
+package scraper
+
+import (
+	"context"
+
+	"meowyplayer.com/utility/network/fileformat"
+)
+
+type PlayListScraper interface {
+	SearchPlayList(ctx context.Context, url string) (*fileformat.PlayListResult, error)
+}
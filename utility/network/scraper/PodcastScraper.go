@@ -0,0 +1,240 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"meowyplayer.com/utility/network/fileformat"
+	"meowyplayer.com/utility/network/httpclient"
+)
+
+var ErrNotFeed = errors.New("not a RSS or Atom feed")
+
+// https://www.rssboard.org/rss-specification
+// the itunes fields come first, so that they are not taken by the plain fields of the same name
+type rssFeed struct {
+	Channel struct {
+		ITunesTitle string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd title"`
+		Title       string `xml:"title"`
+		Author      string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd author"`
+		ITunesImage struct {
+			Href string `xml:"href,attr"`
+		} `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+		Image struct {
+			URL string `xml:"url"`
+		} `xml:"image"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type rssItem struct {
+	ITunesTitle string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd title"`
+	Title       string `xml:"title"`
+	GUID        string `xml:"guid"`
+	Link        string `xml:"link"`
+	Description string `xml:"description"`
+	PubDate     string `xml:"pubDate"`
+	Duration    string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+	ITunesImage struct {
+		Href string `xml:"href,attr"`
+	} `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+	Enclosure struct {
+		URL  string `xml:"url,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"enclosure"`
+}
+
+// https://www.rfc-editor.org/rfc/rfc4287
+type atomFeed struct {
+	Title   string      `xml:"title"`
+	Author  string      `xml:"author>name"`
+	Logo    string      `xml:"logo"`
+	Icon    string      `xml:"icon"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID        string    `xml:"id"`
+	Title     string    `xml:"title"`
+	Author    string    `xml:"author>name"`
+	Published time.Time `xml:"published"`
+	Updated   time.Time `xml:"updated"`
+	Summary   string    `xml:"summary"`
+	Links     []struct {
+		Rel  string `xml:"rel,attr"`
+		Href string `xml:"href,attr"`
+	} `xml:"link"`
+}
+
+/*
+Read the episodes of a podcast from its RSS or Atom feed.
+The entries without an audio enclosure are not episodes and are left out.
+*/
+type PodcastScraper struct{}
+
+func NewPodcastScraper() *PodcastScraper {
+	return &PodcastScraper{}
+}
+
+func (s *PodcastScraper) SearchPlayList(ctx context.Context, feedURL string) (*fileformat.PlayListResult, error) {
+	feedURL = strings.TrimSpace(feedURL)
+	networkLog.Info("scraping podcast", "url", feedURL)
+	resp, err := httpclient.Get(ctx, feedURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *fileformat.PlayListResult
+	switch rootElement(data) {
+	case "rss":
+		feed := rssFeed{}
+		if err := xml.Unmarshal(data, &feed); err != nil {
+			return nil, err
+		}
+		result = parseRSSFeed(&feed)
+	case "feed":
+		feed := atomFeed{}
+		if err := xml.Unmarshal(data, &feed); err != nil {
+			return nil, err
+		}
+		result = parseAtomFeed(&feed)
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrNotFeed, feedURL)
+	}
+	result.PlayListID = feedURL
+	networkLog.Debug("scraped episodes", "count", len(result.Videos))
+	return result, nil
+}
+
+// the name of the first element, empty if the data is not xml
+func rootElement(data []byte) string {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local
+		}
+	}
+}
+
+func parseRSSFeed(feed *rssFeed) *fileformat.PlayListResult {
+	channel := &feed.Channel
+	result := &fileformat.PlayListResult{
+		ChannelTitle: strings.TrimSpace(channel.Author),
+		Title:        strings.TrimSpace(channel.Title),
+		ThumbnailURL: firstNonEmpty(channel.ITunesImage.Href, channel.Image.URL),
+	}
+
+	for _, item := range channel.Items {
+		if item.Enclosure.URL == "" {
+			continue
+		}
+		published, _ := parsePubDate(item.PubDate)
+		length, _ := parseDuration(item.Duration)
+		result.Videos = append(result.Videos, fileformat.VideoResult{
+			VideoID:      firstNonEmpty(strings.TrimSpace(item.GUID), item.Enclosure.URL),
+			URL:          strings.TrimSpace(item.Link),
+			ChannelTitle: result.ChannelTitle,
+			Title:        strings.TrimSpace(firstNonEmpty(item.Title, item.ITunesTitle)),
+			Stats:        episodeStats(published),
+			Description:  htmlText(item.Description),
+			Length:       length,
+			Uploaded:     published,
+			ThumbnailURL: firstNonEmpty(item.ITunesImage.Href, result.ThumbnailURL),
+			AudioURL:     item.Enclosure.URL,
+		})
+	}
+	return result
+}
+
+func parseAtomFeed(feed *atomFeed) *fileformat.PlayListResult {
+	result := &fileformat.PlayListResult{
+		ChannelTitle: strings.TrimSpace(feed.Author),
+		Title:        strings.TrimSpace(feed.Title),
+		ThumbnailURL: firstNonEmpty(feed.Logo, feed.Icon),
+	}
+
+	for _, entry := range feed.Entries {
+		page, enclosure := "", ""
+		for _, link := range entry.Links {
+			switch link.Rel {
+			case "enclosure":
+				enclosure = firstNonEmpty(enclosure, link.Href)
+			case "alternate", "":
+				page = firstNonEmpty(page, link.Href)
+			}
+		}
+		if enclosure == "" {
+			continue
+		}
+
+		published := entry.Published
+		if published.IsZero() {
+			published = entry.Updated
+		}
+		result.Videos = append(result.Videos, fileformat.VideoResult{
+			VideoID:      firstNonEmpty(strings.TrimSpace(entry.ID), enclosure),
+			URL:          page,
+			ChannelTitle: strings.TrimSpace(firstNonEmpty(entry.Author, feed.Author)),
+			Title:        strings.TrimSpace(entry.Title),
+			Stats:        episodeStats(published),
+			Description:  htmlText(entry.Summary),
+			Uploaded:     published,
+			ThumbnailURL: result.ThumbnailURL,
+			AudioURL:     enclosure,
+		})
+	}
+	return result
+}
+
+// the feeds in the wild don't always follow RFC 822 to the letter
+func parsePubDate(text string) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	layouts := []string{time.RFC1123Z, time.RFC1123, "Mon, 2 Jan 2006 15:04:05 -0700", "Mon, 2 Jan 2006 15:04:05 MST", "2 Jan 2006 15:04:05 -0700", time.RFC3339}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unknown date format: %q", text)
+}
+
+func episodeStats(published time.Time) string {
+	if published.IsZero() {
+		return "unknown date"
+	}
+	return published.Format(time.DateOnly)
+}
+
+// the descriptions are often html, only the text is kept
+func htmlText(text string) string {
+	node, err := html.Parse(strings.NewReader(text))
+	if err != nil {
+		return strings.TrimSpace(text)
+	}
+	return textContent(node)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
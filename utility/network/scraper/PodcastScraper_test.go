@@ -0,0 +1,122 @@
+package scraper_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"meowyplayer.com/utility/network/scraper"
+)
+
+// serve the recorded feeds, with the links pointing back to the local server
+func newPodcastServer(t *testing.T) *httptest.Server {
+	feeds := map[string]string{}
+	for path, file := range map[string]string{"/rss": "testdata/podcast_rss.xml", "/atom": "testdata/podcast_atom.xml"} {
+		feed, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("%v\n", err)
+		}
+		feeds[path] = string(feed)
+	}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch feed, ok := feeds[r.URL.Path]; {
+		case ok:
+			w.Write([]byte(strings.ReplaceAll(feed, "{{host}}", server.URL)))
+		case r.URL.Path == "/page":
+			w.Write([]byte("<html><body>not a feed</body></html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPodcastRSS(t *testing.T) {
+	server := newPodcastServer(t)
+	result, err := scraper.NewPodcastScraper().SearchPlayList(context.Background(), server.URL+"/rss")
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if result.Title != "Meowy Radio" || result.ChannelTitle != "Meowy & Friends" || result.PlayListID != server.URL+"/rss" {
+		t.Errorf("unexpected podcast: %+v\n", result)
+	}
+	if result.ThumbnailURL != server.URL+"/image/cover.png" {
+		t.Errorf("expected the itunes image to be the cover, got %v\n", result.ThumbnailURL)
+	}
+
+	//the announcement has no audio
+	if len(result.Videos) != 2 {
+		t.Fatalf("expected 2 episodes, got %v\n", len(result.Videos))
+	}
+
+	episode := result.Videos[0]
+	if episode.VideoID != "meowy-radio-2" || episode.Title != "Episode 2: Cat Naps" || episode.URL != server.URL+"/episodes/2" {
+		t.Errorf("unexpected episode: %+v\n", episode)
+	}
+	if episode.AudioURL != server.URL+"/audio/2.mp3" || episode.ThumbnailURL != server.URL+"/image/2.png" {
+		t.Errorf("unexpected enclosure or image: %+v\n", episode)
+	}
+	if episode.Description != "All about cat naps ." {
+		t.Errorf("expected the html to be stripped, got %q\n", episode.Description)
+	}
+	if !episode.Uploaded.Equal(time.Date(2024, 1, 2, 8, 30, 0, 0, time.UTC)) || episode.Length != time.Hour+2*time.Minute+3*time.Second {
+		t.Errorf("unexpected date or length: %v %v\n", episode.Uploaded, episode.Length)
+	}
+
+	//the episode without a guid is identified by its enclosure, and inherits the podcast image
+	episode = result.Videos[1]
+	if episode.VideoID != server.URL+"/audio/1.mp3" || episode.ThumbnailURL != result.ThumbnailURL {
+		t.Errorf("unexpected episode: %+v\n", episode)
+	}
+	if episode.Length != 754*time.Second || episode.Stats != "2023-12-31" {
+		t.Errorf("unexpected length or stats: %v %v\n", episode.Length, episode.Stats)
+	}
+}
+
+func TestPodcastAtom(t *testing.T) {
+	server := newPodcastServer(t)
+	result, err := scraper.NewPodcastScraper().SearchPlayList(context.Background(), server.URL+"/atom")
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if result.Title != "Meowy Talks" || result.ChannelTitle != "Meowy" || result.ThumbnailURL != server.URL+"/image/logo.png" {
+		t.Errorf("unexpected podcast: %+v\n", result)
+	}
+	if len(result.Videos) != 2 {
+		t.Fatalf("expected 2 episodes, got %v\n", len(result.Videos))
+	}
+
+	episode := result.Videos[0]
+	if episode.VideoID != "urn:meowy-talks:2" || episode.URL != server.URL+"/talks/2" || episode.AudioURL != server.URL+"/audio/talk2.mp3" {
+		t.Errorf("unexpected episode: %+v\n", episode)
+	}
+	if episode.Stats != "2024-02-02" || episode.ChannelTitle != "Meowy" {
+		t.Errorf("expected the update time to stand in for the publish time, got %+v\n", episode)
+	}
+
+	//the publish time is preferred over the update time
+	episode = result.Videos[1]
+	if episode.URL != server.URL+"/talks/1" || !episode.Uploaded.Equal(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected episode: %+v\n", episode)
+	}
+}
+
+func TestPodcastNotFeed(t *testing.T) {
+	server := newPodcastServer(t)
+	if _, err := scraper.NewPodcastScraper().SearchPlayList(context.Background(), server.URL+"/page"); !errors.Is(err, scraper.ErrNotFeed) {
+		t.Errorf("expected %v, got %v\n", scraper.ErrNotFeed, err)
+	}
+	if _, err := scraper.NewPodcastScraper().SearchPlayList(context.Background(), server.URL+"/missing"); err == nil {
+		t.Errorf("expected the missing feed to fail\n")
+	}
+}
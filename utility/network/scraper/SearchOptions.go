@@ -0,0 +1,117 @@
+package scraper
+
+import (
+	"strings"
+	"time"
+
+	"meowyplayer.com/utility/network/fileformat"
+)
+
+type Duration uint8
+
+const (
+	AnyDuration    Duration = iota
+	ShortDuration           //under 4 minutes
+	MediumDuration          //4 to 20 minutes
+	LongDuration            //over 20 minutes
+)
+
+var Durations = []Duration{AnyDuration, ShortDuration, MediumDuration, LongDuration}
+
+func (d Duration) String() string {
+	return [...]string{"Any Length", "Short", "Medium", "Long"}[d]
+}
+
+// whether the video length falls into the duration
+func (d Duration) Contains(length time.Duration) bool {
+	switch d {
+	case ShortDuration:
+		return length < 4*time.Minute
+	case MediumDuration:
+		return 4*time.Minute <= length && length <= 20*time.Minute
+	case LongDuration:
+		return length > 20*time.Minute
+	}
+	return true
+}
+
+type UploadDate uint8
+
+const (
+	AnyDate UploadDate = iota
+	Today
+	ThisWeek
+	ThisMonth
+	ThisYear
+)
+
+var UploadDates = []UploadDate{AnyDate, Today, ThisWeek, ThisMonth, ThisYear}
+
+func (u UploadDate) String() string {
+	return [...]string{"Any Date", "Today", "This Week", "This Month", "This Year"}[u]
+}
+
+// the earliest upload time within the date, zero for any date
+func (u UploadDate) Since(now time.Time) time.Time {
+	switch u {
+	case Today:
+		return now.AddDate(0, 0, -1)
+	case ThisWeek:
+		return now.AddDate(0, 0, -7)
+	case ThisMonth:
+		return now.AddDate(0, -1, 0)
+	case ThisYear:
+		return now.AddDate(-1, 0, 0)
+	}
+	return time.Time{}
+}
+
+type SortOrder uint8
+
+const (
+	SortByRelevance SortOrder = iota
+	SortByViews
+	SortByDate
+)
+
+var SortOrders = []SortOrder{SortByRelevance, SortByViews, SortByDate}
+
+func (s SortOrder) String() string {
+	return [...]string{"Relevance", "Views", "Upload Date"}[s]
+}
+
+/*
+The options of an online search, the zero value searches the first page by relevance.
+The scrapers send what the site understands and filter the rest out of the results themselves,
+so a filtered page may come back empty even though there are more pages after it.
+*/
+type SearchOptions struct {
+	Page       int
+	Duration   Duration
+	UploadDate UploadDate
+	Channel    string //matches the channel id or a part of the channel title
+	Sort       SortOrder
+}
+
+// whether any filter is set, the pages of an unfiltered search only run empty at the end
+func (o *SearchOptions) Filtered() bool {
+	return o.Duration != AnyDuration || o.UploadDate != AnyDate || o.Channel != ""
+}
+
+// apply the filters on the client side, the results without a known upload date pass the date filter
+func (o *SearchOptions) Match(result *fileformat.VideoResult) bool {
+	if !o.Duration.Contains(result.Length) {
+		return false
+	}
+	if since := o.UploadDate.Since(time.Now()); !result.Uploaded.IsZero() && result.Uploaded.Before(since) {
+		return false
+	}
+	if channel := strings.TrimSpace(o.Channel); channel != "" {
+		return result.ChannelID == channel || strings.Contains(strings.ToLower(result.ChannelTitle), strings.ToLower(channel))
+	}
+	return true
+}
+
+func (o *SearchOptions) page() int {
+	return max(o.Page, 1)
+}
@@ -0,0 +1,16 @@
+package scraper
+
+import (
+	"context"
+
+	"meowyplayer.com/utility/network/fileformat"
+)
+
+/*
+Resolve a pasted video url into its metadata without searching.
+*/
+type VideoResolver interface {
+	// return the video id of the url (or the bare id), false if the url doesn't belong to the site
+	ParseVideoURL(rawURL string) (string, bool)
+	ResolveVideo(ctx context.Context, videoID string) (*fileformat.VideoResult, error)
+}
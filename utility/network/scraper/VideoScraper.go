@@ -1,7 +1,15 @@
 package scraper
 
-import "meowyplayer.com/utility/network/fileformat"
+import (
+	"context"
 
+	"meowyplayer.com/utility/logger"
+	"meowyplayer.com/utility/network/fileformat"
+)
+
+var networkLog = logger.Subsystem(logger.Network)
+
+// the pages start from 1, a page past the last one has no results
 type VideoScraper interface {
-	Search(title string) ([]fileformat.VideoResult, error)
+	Search(ctx context.Context, title string, options SearchOptions) ([]fileformat.VideoResult, error)
 }
@@ -0,0 +1,172 @@
+package scraper
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"meowyplayer.com/utility/network/fileformat"
+	"meowyplayer.com/utility/network/httpclient"
+)
+
+const YouTubeHost = `https://www.youtube.com`
+
+// the feeds list this many of the latest entries at most, and can't be paged
+const youtubeFeedLimit = 15
+
+// the channels named by a handle or a custom url are resolved to their id from the channel page
+const channelPageKey = "channel_page"
+
+var canonicalChannelRegex = regexp.MustCompile(`<link rel="canonical" href="[^"]*/channel/([\w-]+)"`)
+
+// https://developers.google.com/youtube/v3/guides/push_notifications
+type youtubeFeed struct {
+	PlayListID string             `xml:"http://www.youtube.com/xml/schemas/2015 playlistId"`
+	ChannelID  string             `xml:"http://www.youtube.com/xml/schemas/2015 channelId"`
+	Title      string             `xml:"title"`
+	Author     string             `xml:"author>name"`
+	Entries    []youtubeFeedEntry `xml:"entry"`
+}
+
+type youtubeFeedEntry struct {
+	VideoID   string    `xml:"http://www.youtube.com/xml/schemas/2015 videoId"`
+	ChannelID string    `xml:"http://www.youtube.com/xml/schemas/2015 channelId"`
+	Title     string    `xml:"title"`
+	Author    string    `xml:"author>name"`
+	Published time.Time `xml:"published"`
+	Media     struct {
+		Thumbnail struct {
+			URL string `xml:"url,attr"`
+		} `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+		Description string `xml:"http://search.yahoo.com/mrss/ description"`
+		Statistics  struct {
+			Views string `xml:"views,attr"`
+		} `xml:"http://search.yahoo.com/mrss/ community>statistics"`
+	} `xml:"http://search.yahoo.com/mrss/ group"`
+}
+
+/*
+Scrape the play list or the channel uploads from the YouTube feeds.
+The feeds only list the latest 15 entries and have no video length, the result is marked truncated when it is full.
+*/
+type YouTubeFeedScraper struct {
+	host string
+}
+
+func NewYouTubeFeedScraper(host string) *YouTubeFeedScraper {
+	return &YouTubeFeedScraper{host}
+}
+
+// return the feed query of a play list or channel url, false if the url is neither
+// the channel urls are /channel/<id>, /user/<name>, /c/<name> or /@<handle>
+func ParsePlayListURL(rawURL string) (url.Values, bool) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return nil, false
+	}
+	if list := u.Query().Get("list"); list != "" {
+		return url.Values{"playlist_id": {list}}, true
+	}
+
+	segments := append(strings.Split(strings.Trim(u.Path, "/"), "/"), "")
+	switch {
+	case segments[0] == "channel" && segments[1] != "":
+		return url.Values{"channel_id": {segments[1]}}, true
+	case segments[0] == "user" && segments[1] != "":
+		return url.Values{"user": {segments[1]}}, true
+	case segments[0] == "c" && segments[1] != "":
+		return url.Values{channelPageKey: {"/c/" + segments[1]}}, true
+	case len(segments[0]) > 1 && segments[0][0] == '@':
+		return url.Values{channelPageKey: {"/" + segments[0]}}, true
+	}
+	return nil, false
+}
+
+func (s *YouTubeFeedScraper) SearchPlayList(ctx context.Context, rawURL string) (*fileformat.PlayListResult, error) {
+	query, ok := ParsePlayListURL(rawURL)
+	if !ok {
+		return nil, fmt.Errorf("not a play list or channel url: %v", rawURL)
+	}
+	if page := query.Get(channelPageKey); page != "" {
+		channelID, err := resolveChannelID(ctx, s.host, page)
+		if err != nil {
+			return nil, err
+		}
+		query = url.Values{"channel_id": {channelID}}
+	}
+
+	feedURL := s.host + `/feeds/videos.xml?` + query.Encode()
+	networkLog.Info("scraping YouTube feed", "url", feedURL)
+	resp, err := httpclient.Get(ctx, feedURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	feed := youtubeFeed{}
+	if err := xml.NewDecoder(resp.Body).Decode(&feed); err != nil {
+		return nil, err
+	}
+	return s.parseFeed(&feed), nil
+}
+
+// the feeds only take the channel id, which is read from the canonical link of the channel page
+func resolveChannelID(ctx context.Context, host string, page string) (string, error) {
+	networkLog.Info("resolving YouTube channel", "page", page)
+	resp, err := httpclient.Get(ctx, host+page, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	match := canonicalChannelRegex.FindSubmatch(content)
+	if match == nil {
+		return "", fmt.Errorf("%w: no channel id found on %v", ErrLayoutChanged, page)
+	}
+	return string(match[1]), nil
+}
+
+func (s *YouTubeFeedScraper) parseFeed(feed *youtubeFeed) *fileformat.PlayListResult {
+	result := &fileformat.PlayListResult{
+		PlayListID:   feed.PlayListID,
+		ChannelID:    feed.ChannelID,
+		ChannelTitle: html.UnescapeString(feed.Author),
+		Title:        html.UnescapeString(feed.Title),
+		Videos:       make([]fileformat.VideoResult, len(feed.Entries)),
+		Truncated:    len(feed.Entries) >= youtubeFeedLimit,
+	}
+	networkLog.Debug("scraping results", "count", len(feed.Entries))
+
+	for i, entry := range feed.Entries {
+		views, _ := strconv.ParseInt(entry.Media.Statistics.Views, 10, 64)
+		result.Videos[i] = fileformat.VideoResult{
+			VideoID:      entry.VideoID,
+			URL:          youtubeVideoURL(entry.VideoID),
+			ChannelID:    entry.ChannelID,
+			ChannelTitle: html.UnescapeString(entry.Author),
+			Title:        html.UnescapeString(entry.Title),
+			Views:        views,
+			Stats:        fmt.Sprintf("%v views | %v", entry.Media.Statistics.Views, entry.Published.Format(time.DateOnly)),
+			Description:  html.UnescapeString(entry.Media.Description),
+			Uploaded:     entry.Published,
+			ThumbnailURL: entry.Media.Thumbnail.URL,
+		}
+	}
+
+	//the play list thumbnail is the thumbnail of its first video
+	if len(result.Videos) > 0 {
+		result.ThumbnailURL = result.Videos[0].ThumbnailURL
+	}
+	return result
+}
@@ -0,0 +1,134 @@
+package scraper_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"meowyplayer.com/utility/network/scraper"
+)
+
+// serve the recorded feed, with the thumbnails pointing back to the local server
+func newFeedServer(t *testing.T) *httptest.Server {
+	feed, err := os.ReadFile("testdata/playlist.xml")
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/feeds/videos.xml" && (r.URL.Query().Get("playlist_id") == "PLtest" || r.URL.Query().Get("channel_id") == "UCtest"):
+			w.Write([]byte(strings.ReplaceAll(string(feed), "{{host}}", server.URL)))
+		case r.URL.Path == "/feeds/videos.xml" && r.URL.Query().Get("playlist_id") == "PLfull":
+			w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom">` + strings.Repeat("<entry><title>Renai Circulation</title></entry>", 15) + `</feed>`))
+		case r.URL.Path == "/@meowy" || r.URL.Path == "/c/meowy":
+			w.Write([]byte(`<html><head><link rel="canonical" href="https://www.youtube.com/channel/UCtest"></head></html>`))
+		case r.URL.Path == "/@broken":
+			w.Write([]byte(`<html><head></head></html>`))
+		case strings.HasPrefix(r.URL.Path, "/vi/"):
+			w.Write([]byte("thumbnail"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestParsePlayListURL(t *testing.T) {
+	cases := map[string]string{
+		"https://www.youtube.com/playlist?list=PLtest":            "playlist_id=PLtest",
+		"https://www.youtube.com/watch?v=auQxNYJ07Lc&list=PLtest": "playlist_id=PLtest",
+		"https://www.youtube.com/channel/UCtest":                  "channel_id=UCtest",
+		"https://www.youtube.com/channel/UCtest/videos":           "channel_id=UCtest",
+		"https://www.youtube.com/user/meowy":                      "user=meowy",
+		"https://www.youtube.com/c/meowy/videos":                  "channel_page=%2Fc%2Fmeowy",
+		"https://www.youtube.com/@meowy":                          "channel_page=%2F%40meowy",
+		"https://www.youtube.com/@":                               "",
+		"https://www.youtube.com/channel/":                        "",
+		"  https://youtube.com/playlist?list=PLtest&si=abc  ":     "playlist_id=PLtest",
+		"renai circulation":                                       "",
+		"https://www.youtube.com/watch?v=auQxNYJ07Lc":             "",
+	}
+
+	for rawURL, expected := range cases {
+		query, ok := scraper.ParsePlayListURL(rawURL)
+		if ok != (expected != "") || (ok && query.Encode() != expected) {
+			t.Errorf("%q: expected %q, got %q\n", rawURL, expected, query.Encode())
+		}
+	}
+}
+
+func TestSearchPlayList(t *testing.T) {
+	server := newFeedServer(t)
+	result, err := scraper.NewYouTubeFeedScraper(server.URL).SearchPlayList(context.Background(), "https://www.youtube.com/playlist?list=PLtest")
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if result.Title != "Monogatari Openings" || result.ChannelTitle != "Meowy & Friends" || result.PlayListID != "PLtest" {
+		t.Errorf("unexpected play list: %+v\n", result)
+	}
+	if len(result.Videos) != 2 {
+		t.Fatalf("expected 2 videos, got %v\n", len(result.Videos))
+	}
+
+	video := result.Videos[0]
+	if video.VideoID != "auQxNYJ07Lc" || video.Title != "Renai Circulation" || video.ChannelID != "UCtest" || video.Description != "Nadeko's opening" {
+		t.Errorf("unexpected video: %+v\n", video)
+	}
+	if video.Views != 12345 || video.Uploaded.Format(time.DateOnly) != "2023-01-02" {
+		t.Errorf("unexpected views or upload time: %+v\n", video)
+	}
+	if video.Stats != "12345 views | 2023-01-02" {
+		t.Errorf("unexpected stats: %v\n", video.Stats)
+	}
+	if video.ThumbnailURL != server.URL+"/vi/auQxNYJ07Lc/hqdefault.jpg" {
+		t.Errorf("unexpected thumbnail: %v\n", video.ThumbnailURL)
+	}
+	if result.ThumbnailURL != video.ThumbnailURL {
+		t.Errorf("play list thumbnail should be the first video thumbnail\n")
+	}
+}
+
+func TestSearchPlayListNotFound(t *testing.T) {
+	server := newFeedServer(t)
+	if _, err := scraper.NewYouTubeFeedScraper(server.URL).SearchPlayList(context.Background(), "https://www.youtube.com/playlist?list=PLmissing"); err == nil {
+		t.Fatalf("expected an error for the missing play list\n")
+	}
+}
+
+func TestSearchPlayListChannelPage(t *testing.T) {
+	server := newFeedServer(t)
+	for _, rawURL := range []string{"https://www.youtube.com/@meowy", "https://www.youtube.com/c/meowy"} {
+		result, err := scraper.NewYouTubeFeedScraper(server.URL).SearchPlayList(context.Background(), rawURL)
+		if err != nil {
+			t.Fatalf("%v: %v\n", rawURL, err)
+		}
+		if len(result.Videos) != 2 || result.Truncated {
+			t.Errorf("%v: unexpected play list: %+v\n", rawURL, result)
+		}
+	}
+
+	_, err := scraper.NewYouTubeFeedScraper(server.URL).SearchPlayList(context.Background(), "https://www.youtube.com/@broken")
+	if !errors.Is(err, scraper.ErrLayoutChanged) {
+		t.Errorf("expected the layout changed error, got %v\n", err)
+	}
+}
+
+func TestSearchPlayListTruncated(t *testing.T) {
+	server := newFeedServer(t)
+	result, err := scraper.NewYouTubeFeedScraper(server.URL).SearchPlayList(context.Background(), "https://www.youtube.com/playlist?list=PLfull")
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if len(result.Videos) != 15 || !result.Truncated {
+		t.Errorf("expected the full feed to be marked truncated, got %v videos\n", len(result.Videos))
+	}
+}
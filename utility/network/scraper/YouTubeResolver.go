@@ -0,0 +1,118 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"meowyplayer.com/utility/network/fileformat"
+	"meowyplayer.com/utility/network/httpclient"
+)
+
+// https://oembed.com/
+type youtubeOEmbed struct {
+	Title        string `json:"title"`
+	AuthorName   string `json:"author_name"`
+	AuthorURL    string `json:"author_url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+/*
+Resolve the YouTube video urls through the oEmbed endpoint.
+The endpoint has no video length, so it is left for the downloader to estimate.
+It links the channel by its handle, which is resolved to the channel id shared with the other sources.
+*/
+type YouTubeResolver struct {
+	host    string
+	idRegex *regexp.Regexp
+}
+
+func NewYouTubeResolver(host string) *YouTubeResolver {
+	return &YouTubeResolver{host, regexp.MustCompile(`^[\w-]{11}$`)}
+}
+
+// accept watch, youtu.be, shorts, embed and live urls along with the bare id, the timestamps and play lists are ignored
+func (r *YouTubeResolver) ParseVideoURL(rawURL string) (string, bool) {
+	rawURL = strings.TrimSpace(rawURL)
+	if r.idRegex.MatchString(rawURL) {
+		return rawURL, true
+	}
+
+	//tolerate the urls pasted without scheme
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", false
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	videoID := ""
+	switch host {
+	case "youtu.be":
+		videoID = segments[0]
+	case "youtube.com", "m.youtube.com", "music.youtube.com", "youtube-nocookie.com":
+		switch {
+		case segments[0] == "watch":
+			videoID = u.Query().Get("v")
+		case len(segments) > 1 && (segments[0] == "shorts" || segments[0] == "embed" || segments[0] == "live" || segments[0] == "v"):
+			videoID = segments[1]
+		}
+	}
+
+	if !r.idRegex.MatchString(videoID) {
+		return "", false
+	}
+	return videoID, true
+}
+
+func (r *YouTubeResolver) ResolveVideo(ctx context.Context, videoID string) (*fileformat.VideoResult, error) {
+	videoURL := youtubeVideoURL(videoID)
+	oembedURL := r.host + `/oembed?` + url.Values{"url": {videoURL}, "format": {"json"}}.Encode()
+	networkLog.Info("resolving YouTube video", "url", oembedURL)
+
+	data, err := httpclient.ReadAll(ctx, oembedURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't resolve the video %v: %w", videoID, err)
+	}
+	oembed := youtubeOEmbed{}
+	if err := json.Unmarshal(data, &oembed); err != nil {
+		return nil, err
+	}
+
+	//the video is still downloadable without its channel
+	channelID, err := r.resolveChannelID(ctx, oembed.AuthorURL)
+	if err != nil {
+		networkLog.Warn("couldn't resolve the channel", "url", oembed.AuthorURL, "err", err)
+	}
+	result := &fileformat.VideoResult{
+		VideoID:      videoID,
+		URL:          videoURL,
+		Title:        oembed.Title,
+		ChannelTitle: oembed.AuthorName,
+		ChannelID:    channelID,
+		ThumbnailURL: oembed.ThumbnailURL,
+	}
+	return result, nil
+}
+
+// the author url is the channel page, either by its id or by the handle
+func (r *YouTubeResolver) resolveChannelID(ctx context.Context, authorURL string) (string, error) {
+	u, err := url.Parse(authorURL)
+	if err != nil {
+		return "", err
+	}
+	if segments := strings.Split(strings.Trim(u.Path, "/"), "/"); len(segments) == 2 && segments[0] == "channel" {
+		return segments[1], nil
+	}
+	return resolveChannelID(ctx, r.host, u.Path)
+}
+
+func youtubeVideoURL(videoID string) string {
+	return YouTubeHost + `/watch?` + url.Values{"v": {videoID}}.Encode()
+}
@@ -0,0 +1,93 @@
+package scraper_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"meowyplayer.com/utility/network/scraper"
+)
+
+func TestParseVideoURL(t *testing.T) {
+	resolver := scraper.NewYouTubeResolver(scraper.YouTubeHost)
+	valid := []string{
+		"auQxNYJ07Lc",
+		"https://www.youtube.com/watch?v=auQxNYJ07Lc",
+		"https://www.youtube.com/watch?v=auQxNYJ07Lc&t=42s",
+		"https://www.youtube.com/watch?v=auQxNYJ07Lc&list=PLtest&index=3",
+		"https://m.youtube.com/watch?feature=share&v=auQxNYJ07Lc",
+		"https://music.youtube.com/watch?v=auQxNYJ07Lc",
+		"https://youtu.be/auQxNYJ07Lc",
+		"https://youtu.be/auQxNYJ07Lc?t=42",
+		"https://youtu.be/auQxNYJ07Lc?si=shared&list=PLtest",
+		"https://www.youtube.com/shorts/auQxNYJ07Lc",
+		"https://youtube.com/shorts/auQxNYJ07Lc?feature=share",
+		"https://www.youtube.com/embed/auQxNYJ07Lc?start=10",
+		"https://www.youtube.com/live/auQxNYJ07Lc",
+		"youtube.com/watch?v=auQxNYJ07Lc",
+		" youtu.be/auQxNYJ07Lc ",
+	}
+	for _, rawURL := range valid {
+		if videoID, ok := resolver.ParseVideoURL(rawURL); !ok || videoID != "auQxNYJ07Lc" {
+			t.Errorf("%v: got %v %v\n", rawURL, videoID, ok)
+		}
+	}
+
+	invalid := []string{
+		"renai circulation",
+		"https://www.youtube.com/playlist?list=PLtest",
+		"https://www.youtube.com/channel/UCtest",
+		"https://www.youtube.com/watch?v=short",
+		"https://www.bilibili.com/video/BV17x411w7KC",
+		"https://example.com/watch?v=auQxNYJ07Lc",
+	}
+	for _, rawURL := range invalid {
+		if videoID, ok := resolver.ParseVideoURL(rawURL); ok {
+			t.Errorf("%v: unexpected video id %v\n", rawURL, videoID)
+		}
+	}
+}
+
+func TestResolveVideo(t *testing.T) {
+	oembed, err := os.ReadFile("testdata/youtube_oembed.json")
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/oembed" && r.URL.Query().Get("url") == scraper.YouTubeHost+"/watch?v=auQxNYJ07Lc":
+			w.Write([]byte(strings.ReplaceAll(string(oembed), "{{host}}", server.URL)))
+		case r.URL.Path == "/@MeowyUp":
+			w.Write([]byte(`<html><head><link rel="canonical" href="https://www.youtube.com/channel/UCmeowy"></head></html>`))
+		case r.URL.Path == "/vi/auQxNYJ07Lc/hqdefault.jpg":
+			w.Write([]byte("thumbnail"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	resolver := scraper.NewYouTubeResolver(server.URL)
+	result, err := resolver.ResolveVideo(context.Background(), "auQxNYJ07Lc")
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if result.VideoID != "auQxNYJ07Lc" || result.ChannelTitle != "MeowyUp" || result.ChannelID != "UCmeowy" || !strings.HasPrefix(result.Title, "Renai Circulation") {
+		t.Errorf("unexpected result: %+v\n", result)
+	}
+	if result.URL != "https://www.youtube.com/watch?v=auQxNYJ07Lc" {
+		t.Errorf("unexpected url: %v\n", result.URL)
+	}
+	if result.ThumbnailURL != server.URL+"/vi/auQxNYJ07Lc/hqdefault.jpg" {
+		t.Errorf("unexpected thumbnail: %v\n", result.ThumbnailURL)
+	}
+
+	if _, err := resolver.ResolveVideo(context.Background(), "missingVide"); err == nil {
+		t.Errorf("expected an error for the missing video\n")
+	}
+}
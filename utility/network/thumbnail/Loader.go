@@ -0,0 +1,81 @@
+package thumbnail
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+	"meowyplayer.com/utility/logger"
+	"meowyplayer.com/utility/network/httpclient"
+)
+
+var networkLog = logger.Subsystem(logger.Network)
+
+/*
+Fetch the thumbnails with at most N downloads at a time, the fetched ones are kept in a disk cache.
+*/
+type Loader struct {
+	cachePath string
+	workers   chan struct{}
+}
+
+func NewLoader(cachePath string, workers int) *Loader {
+	return &Loader{cachePath, make(chan struct{}, workers)}
+}
+
+// fetch the thumbnail in the background, the failed or cancelled ones are logged and never call back
+func (l *Loader) Load(ctx context.Context, rawURL string, onLoaded func(fyne.Resource)) {
+	go func() {
+		thumbnail, err := l.Fetch(ctx, rawURL)
+		if err != nil {
+			if ctx.Err() == nil {
+				networkLog.Warn("failed to load the thumbnail", "url", rawURL, "err", err)
+			}
+			return
+		}
+		onLoaded(thumbnail)
+	}()
+}
+
+// fetch the thumbnail from the cache, or download it once a worker is free
+func (l *Loader) Fetch(ctx context.Context, rawURL string) (fyne.Resource, error) {
+	name := cacheName(rawURL)
+	cachePath := filepath.Join(l.cachePath, name)
+	if data, err := os.ReadFile(cachePath); err == nil {
+		return fyne.NewStaticResource(name, data), nil
+	}
+
+	select {
+	case l.workers <- struct{}{}:
+		defer func() { <-l.workers }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	data, err := httpclient.ReadAll(ctx, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	//write aside and rename, so that a partial file is never read from the cache
+	stagePath := cachePath + ".tmp"
+	if err := os.WriteFile(stagePath, data, 0666); err == nil {
+		os.Rename(stagePath, cachePath)
+	}
+	return fyne.NewStaticResource(name, data), nil
+}
+
+// the hash of the url, with the extension kept for the image decoders
+func cacheName(rawURL string) string {
+	hash := sha256.Sum256([]byte(rawURL))
+	name := hex.EncodeToString(hash[:16])
+	if u, err := url.Parse(rawURL); err == nil {
+		name += path.Ext(u.Path)
+	}
+	return name
+}
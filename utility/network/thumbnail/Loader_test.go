@@ -0,0 +1,77 @@
+package thumbnail_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"meowyplayer.com/utility/network/thumbnail"
+)
+
+func TestFetchCached(t *testing.T) {
+	requests := atomic.Int64{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte("thumbnail"))
+	}))
+	defer server.Close()
+
+	loader := thumbnail.NewLoader(t.TempDir(), 2)
+	for i := 0; i < 3; i++ {
+		resource, err := loader.Fetch(context.Background(), server.URL+"/vi/auQxNYJ07Lc/mqdefault.jpg")
+		if err != nil {
+			t.Fatalf("%v\n", err)
+		}
+		if string(resource.Content()) != "thumbnail" {
+			t.Errorf("unexpected content: %v\n", string(resource.Content()))
+		}
+	}
+	if requests.Load() != 1 {
+		t.Errorf("expected the cache to serve the thumbnail, got %v requests\n", requests.Load())
+	}
+}
+
+func TestLoadBounded(t *testing.T) {
+	const workers = 2
+	running := atomic.Int64{}
+	peak := atomic.Int64{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := running.Add(1)
+		defer running.Add(-1)
+		for previous := peak.Load(); current > previous && !peak.CompareAndSwap(previous, current); previous = peak.Load() {
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte(r.URL.Path))
+	}))
+	defer server.Close()
+
+	loader := thumbnail.NewLoader(t.TempDir(), workers)
+	wg := sync.WaitGroup{}
+	wg.Add(8)
+	for i := 0; i < 8; i++ {
+		loader.Load(context.Background(), server.URL+"/vi/"+string(rune('a'+i))+".jpg", func(fyne.Resource) { wg.Done() })
+	}
+	wg.Wait()
+	if peak.Load() > workers {
+		t.Errorf("expected at most %v downloads at a time, got %v\n", workers, peak.Load())
+	}
+}
+
+func TestLoadFailure(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	//the failed thumbnail never calls back
+	loaded := make(chan fyne.Resource, 1)
+	thumbnail.NewLoader(t.TempDir(), 1).Load(context.Background(), server.URL+"/missing.jpg", func(r fyne.Resource) { loaded <- r })
+	select {
+	case <-loaded:
+		t.Errorf("the failed thumbnail is loaded\n")
+	case <-time.After(100 * time.Millisecond):
+	}
+}